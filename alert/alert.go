@@ -0,0 +1,123 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	DEFAULT_HTTP_TIMEOUT = 10 * time.Second
+)
+
+// Alert is a log entry delivered to alert sinks
+type Alert struct {
+	Entry       zapcore.Entry
+	Fields      map[string]interface{}
+	Fingerprint string // identify the same alert, ex: used as pagerduty dedup key
+	Resolved    bool   // the alert stopped occurring
+}
+
+// Sink deliver alert to an alerting service, returned StatusError with 429 or 5xx code will be retried
+type Sink interface {
+	Send(a Alert) error
+}
+
+// SinkFunc is an adapter to use ordinary function as Sink
+type SinkFunc func(a Alert) error
+
+func (f SinkFunc) Send(a Alert) error {
+	return f(a)
+}
+
+// StatusError is returned by sink when alerting service respond with non 2xx status code
+type StatusError struct {
+	Code       int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("alert sink error: %d %s: %s", e.Code, http.StatusText(e.Code), e.Body)
+}
+
+// HTTPStatusCode get response status code
+func (e *StatusError) HTTPStatusCode() int {
+	return e.Code
+}
+
+// SortedKeys get alert field keys in ascending order
+func (a Alert) SortedKeys() []string {
+	keys := make([]string, 0, len(a.Fields))
+	for k := range a.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// truncate cut s to at most max characters, the cut part is replaced with "..."
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	if max <= 3 {
+		return string([]rune(s)[:max])
+	}
+	return string([]rune(s)[:max-3]) + "..."
+}
+
+func newHTTPClient(client *http.Client) *http.Client {
+	if nil != client {
+		return client
+	}
+	return &http.Client{Timeout: DEFAULT_HTTP_TIMEOUT}
+}
+
+func postJSON(client *http.Client, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if nil != err {
+		return err
+	}
+	return post(client, http.MethodPost, url, body, "application/json", headers)
+}
+
+func post(client *http.Client, method string, url string, body []byte, contentType string, headers map[string]string) error {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if nil != err {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if nil != err {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+
+	return &StatusError{
+		Code:       resp.StatusCode,
+		RetryAfter: time.Duration(retryAfter) * time.Second,
+		Body:       string(respBody),
+	}
+}
@@ -0,0 +1,113 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type capturedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// capture is stub alerting service that record received requests
+type capture struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests []capturedRequest
+}
+
+func newCapture(status int, header map[string]string) *capture {
+	c := &capture{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		c.lock.Lock()
+		c.requests = append(c.requests, capturedRequest{Method: r.Method, Path: r.URL.Path, Header: r.Header, Body: body})
+		c.lock.Unlock()
+
+		for k, v := range header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(status)
+	}))
+	return c
+}
+
+// last decode the last request body into v
+func (c *capture) last(t *testing.T, v interface{}) capturedRequest {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if 0 == len(c.requests) {
+		t.Fatal("no request received")
+	}
+	req := c.requests[len(c.requests)-1]
+	if nil != v {
+		if err := json.Unmarshal(req.Body, v); nil != err {
+			t.Fatalf("invalid request body %s: %v", req.Body, err)
+		}
+	}
+	return req
+}
+
+func testAlert() Alert {
+	return Alert{
+		Entry: zapcore.Entry{
+			Level:   zapcore.ErrorLevel,
+			Time:    time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+			Message: "payment failed",
+			Stack:   "main.main\n\t/app/main.go:10",
+		},
+		Fields: map[string]interface{}{
+			"serviceName": "payment",
+			"requestId":   "req-1",
+		},
+		Fingerprint: "abc",
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	c := newCapture(http.StatusTooManyRequests, map[string]string{"Retry-After": "30"})
+	defer c.Close()
+
+	err := postJSON(newHTTPClient(nil), c.URL, map[string]string{}, nil)
+
+	var statusError *StatusError
+	if !errors.As(err, &statusError) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if http.StatusTooManyRequests != statusError.HTTPStatusCode() || 30*time.Second != statusError.RetryAfter {
+		t.Errorf("unexpected status error: %+v", statusError)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		value    string
+		max      int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"truncated value", 10, "truncat..."},
+		{"ééééé", 4, "é..."},
+		{"abc", 2, "ab"},
+	}
+	for _, test := range tests {
+		if result := truncate(test.value, test.max); test.expected != result {
+			t.Errorf("truncate(%q, %d): expected %q, got %q", test.value, test.max, test.expected, result)
+		}
+	}
+}
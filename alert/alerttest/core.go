@@ -0,0 +1,189 @@
+package alerttest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Factory create alert core under test that alert entries at or above minLevel, delivered return alerts delivered
+// by the core, it is called after the core is synced
+type Factory func(t *testing.T, minLevel zapcore.Level) (core zapcore.Core, delivered func() []alert.Alert)
+
+// TestCore run zapcore.Core contract conformance tests against alert core created by factory, the core should
+// deliver every alert without deduplication or rate limiting, example:
+//
+//	func TestSlackCore(t *testing.T) {
+//		alerttest.TestCore(t, func(t *testing.T, minLevel zapcore.Level) (zapcore.Core, func() []alert.Alert) {
+//			recorder := &alerttest.Recorder{}
+//			core := slackcore.New(slackcore.Options{MinLevel: minLevel, Sinks: []alert.Sink{recorder}})
+//			return core, recorder.Alerts
+//		})
+//	}
+func TestCore(t *testing.T, factory Factory) {
+	t.Run("Enabled", func(t *testing.T) { testEnabled(t, factory) })
+	t.Run("Check", func(t *testing.T) { testCheck(t, factory) })
+	t.Run("WriteBelowLevel", func(t *testing.T) { testWriteBelowLevel(t, factory) })
+	t.Run("Write", func(t *testing.T) { testWrite(t, factory) })
+	t.Run("With", func(t *testing.T) { testWith(t, factory) })
+	t.Run("Tee", func(t *testing.T) { testTee(t, factory) })
+}
+
+func testEnabled(t *testing.T, factory Factory) {
+	core, _ := factory(t, zapcore.ErrorLevel)
+	defer syncCore(t, core)
+
+	for _, level := range []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel} {
+		if core.Enabled(level) {
+			t.Errorf("core should not be enabled for %s", level)
+		}
+	}
+	for _, level := range []zapcore.Level{zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel} {
+		if !core.Enabled(level) {
+			t.Errorf("core should be enabled for %s", level)
+		}
+	}
+}
+
+func testCheck(t *testing.T, factory Factory) {
+	core, _ := factory(t, zapcore.ErrorLevel)
+	defer syncCore(t, core)
+
+	if ce := core.Check(entry(zapcore.WarnLevel, "below"), nil); nil != ce {
+		t.Error("Check should not add core below min level")
+	}
+	if ce := core.Check(entry(zapcore.ErrorLevel, "at"), nil); nil == ce {
+		t.Error("Check should add core at min level")
+	}
+}
+
+func testWriteBelowLevel(t *testing.T, factory Factory) {
+	core, delivered := factory(t, zapcore.ErrorLevel)
+
+	// wrapping cores like request log buffer write directly without Check
+	if err := core.Write(entry(zapcore.WarnLevel, "below"), nil); nil != err {
+		t.Errorf("Write returned error: %v", err)
+	}
+	syncCore(t, core)
+
+	if alerts := delivered(); 0 != len(alerts) {
+		t.Errorf("entry below min level should not be delivered, got %d alerts", len(alerts))
+	}
+}
+
+func testWrite(t *testing.T, factory Factory) {
+	core, delivered := factory(t, zapcore.ErrorLevel)
+
+	fields := []zapcore.Field{zap.String("key", "value")}
+	if ce := core.Check(entry(zapcore.ErrorLevel, "boom"), nil); nil != ce {
+		ce.Write(fields...)
+	}
+	syncCore(t, core)
+
+	if 1 != len(fields) || "key" != fields[0].Key {
+		t.Error("Write should not modify the given fields")
+	}
+
+	alerts := delivered()
+	if 1 != len(alerts) {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if "boom" != alerts[0].Entry.Message || zapcore.ErrorLevel != alerts[0].Entry.Level {
+		t.Errorf("unexpected alert entry: %+v", alerts[0].Entry)
+	}
+	if "value" != alerts[0].Fields["key"] {
+		t.Errorf("alert should contain entry fields, got %v", alerts[0].Fields)
+	}
+}
+
+func testWith(t *testing.T, factory Factory) {
+	core, delivered := factory(t, zapcore.ErrorLevel)
+
+	parent := core.With([]zapcore.Field{zap.String("parent", "p")})
+	first := parent.With([]zapcore.Field{zap.String("child", "first")})
+	second := parent.With([]zapcore.Field{zap.String("child", "second")})
+
+	for _, c := range []zapcore.Core{core, parent, first, second} {
+		if err := c.Write(entry(zapcore.ErrorLevel, "boom"), nil); nil != err {
+			t.Errorf("Write returned error: %v", err)
+		}
+	}
+	syncCore(t, core)
+
+	alerts := delivered()
+	if 4 != len(alerts) {
+		t.Fatalf("expected 4 alerts, got %d", len(alerts))
+	}
+
+	expected := []map[string]interface{}{
+		{},
+		{"parent": "p"},
+		{"parent": "p", "child": "first"},
+		{"parent": "p", "child": "second"},
+	}
+	for i, fields := range expected {
+		for key, value := range fields {
+			if value != alerts[i].Fields[key] {
+				t.Errorf("alert %d: expected %s=%v, got %v", i, key, value, alerts[i].Fields[key])
+			}
+		}
+		for _, key := range []string{"parent", "child"} {
+			if _, ok := fields[key]; !ok {
+				if _, ok := alerts[i].Fields[key]; ok {
+					t.Errorf("alert %d: field %s should not be added to this core", i, key)
+				}
+			}
+		}
+	}
+}
+
+func testTee(t *testing.T, factory Factory) {
+	core, delivered := factory(t, zapcore.ErrorLevel)
+	observed, logs := observer.New(zapcore.DebugLevel)
+
+	logger := zap.New(zapcore.NewTee(observed, core)).With(zap.String("with", "w"))
+	logger.Info("info")
+	logger.Error("error", zap.String("key", "value"))
+	if err := logger.Sync(); nil != err {
+		t.Errorf("Sync returned error: %v", err)
+	}
+
+	if alerts := delivered(); 1 != len(alerts) {
+		t.Errorf("expected 1 alert, got %d", len(alerts))
+	}
+
+	entries := logs.AllUntimed()
+	if 2 != len(entries) {
+		t.Fatalf("tee core should receive every entry, got %d", len(entries))
+	}
+	for i, keys := range [][]string{{"with"}, {"with", "key"}} {
+		context := entries[i].Context
+		if len(keys) != len(context) {
+			t.Errorf("alert core should not change tee core fields, got %v", context)
+			continue
+		}
+		for j, key := range keys {
+			if key != context[j].Key {
+				t.Errorf("alert core should not change tee core fields, got %v", context)
+			}
+		}
+	}
+}
+
+func entry(level zapcore.Level, message string) zapcore.Entry {
+	return zapcore.Entry{
+		Level:   level,
+		Time:    time.Now(),
+		Message: message,
+	}
+}
+
+func syncCore(t *testing.T, core zapcore.Core) {
+	if err := core.Sync(); nil != err {
+		t.Errorf("Sync returned error: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package alerttest
+
+import (
+	"sync"
+
+	"github.com/payfazz/fz-sentry/alert"
+)
+
+// Recorder is alert sink that keep every sent alert in memory
+type Recorder struct {
+	lock   sync.Mutex
+	alerts []alert.Alert
+}
+
+func (r *Recorder) Send(a alert.Alert) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+// Alerts get sent alerts in order
+func (r *Recorder) Alerts() []alert.Alert {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]alert.Alert(nil), r.alerts...)
+}
@@ -0,0 +1,100 @@
+package alert
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	MAX_DISCORD_FIELDS      = 25
+	MAX_DISCORD_TITLE       = 256
+	MAX_DISCORD_DESCRIPTION = 4096
+	MAX_DISCORD_FIELD_NAME  = 256
+	MAX_DISCORD_FIELD_VALUE = 1024
+	MAX_DISCORD_EMBED       = 6000 // total characters of title, description, field names and field values
+)
+
+var discordColor = map[zapcore.Level]int{
+	zapcore.DebugLevel:  0x9B30FF,
+	zapcore.InfoLevel:   0x2EB886,
+	zapcore.WarnLevel:   0xDAA038,
+	zapcore.ErrorLevel:  0xA30200,
+	zapcore.DPanicLevel: 0xA30200,
+	zapcore.PanicLevel:  0xA30200,
+	zapcore.FatalLevel:  0xA30200,
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Timestamp   string         `json:"timestamp"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+// DiscordSink send alert as embed to discord webhook, http client is optional
+func DiscordSink(webhookURL string, client *http.Client) Sink {
+	client = newHTTPClient(client)
+
+	return SinkFunc(func(a Alert) error {
+		title := fmt.Sprintf("[%s] %s", a.Entry.Level.CapitalString(), a.Entry.Message)
+		if a.Resolved {
+			title = "[RESOLVED] " + a.Entry.Message
+		}
+		embed := discordEmbed{
+			Title:     truncate(title, MAX_DISCORD_TITLE),
+			Color:     discordColor[a.Entry.Level],
+			Timestamp: a.Entry.Time.Format(time.RFC3339),
+		}
+		for _, key := range a.SortedKeys() {
+			if len(embed.Fields) >= MAX_DISCORD_FIELDS {
+				break
+			}
+			value := fmt.Sprint(a.Fields[key])
+			if "" == value {
+				value = "-"
+			}
+			embed.Fields = append(embed.Fields, discordField{
+				Name:   truncate(key, MAX_DISCORD_FIELD_NAME),
+				Value:  truncate(value, MAX_DISCORD_FIELD_VALUE),
+				Inline: true,
+			})
+		}
+
+		// fields are kept over stack trace, the stack trace use the remaining characters
+		size := utf8.RuneCountInString(embed.Title)
+		for i, field := range embed.Fields {
+			fieldSize := utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+			if size+fieldSize > MAX_DISCORD_EMBED {
+				embed.Fields = embed.Fields[:i]
+				break
+			}
+			size += fieldSize
+		}
+		if remaining := MAX_DISCORD_EMBED - size; "" != a.Entry.Stack && remaining > 10 {
+			if remaining > MAX_DISCORD_DESCRIPTION {
+				remaining = MAX_DISCORD_DESCRIPTION
+			}
+			embed.Description = "```" + truncate(a.Entry.Stack, remaining-6) + "```"
+		}
+
+		return postJSON(client, webhookURL, discordMessage{
+			Embeds: []discordEmbed{embed},
+		}, nil)
+	})
+}
@@ -0,0 +1,76 @@
+package alert
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDiscordSink(t *testing.T) {
+	c := newCapture(http.StatusNoContent, nil)
+	defer c.Close()
+
+	if err := DiscordSink(c.URL, nil).Send(testAlert()); nil != err {
+		t.Fatal(err)
+	}
+
+	var msg discordMessage
+	c.last(t, &msg)
+	if 1 != len(msg.Embeds) {
+		t.Fatalf("expected 1 embed, got %d", len(msg.Embeds))
+	}
+	embed := msg.Embeds[0]
+	if "[ERROR] payment failed" != embed.Title || 0xA30200 != embed.Color || 2 != len(embed.Fields) {
+		t.Errorf("unexpected embed: %+v", embed)
+	}
+	if !strings.HasPrefix(embed.Description, "```main.main") {
+		t.Errorf("stack should be sent as description, got %s", embed.Description)
+	}
+}
+
+func TestDiscordSinkLimits(t *testing.T) {
+	c := newCapture(http.StatusNoContent, nil)
+	defer c.Close()
+
+	a := testAlert()
+	a.Entry.Message = strings.Repeat("m", 1000)
+	a.Entry.Stack = strings.Repeat("s", 10000)
+	a.Fields = map[string]interface{}{"empty": ""}
+	for i := 0; i < 40; i++ {
+		a.Fields[fmt.Sprintf("field%02d", i)] = strings.Repeat("v", 2000)
+	}
+
+	if err := DiscordSink(c.URL, nil).Send(a); nil != err {
+		t.Fatal(err)
+	}
+
+	var msg discordMessage
+	c.last(t, &msg)
+	embed := msg.Embeds[0]
+
+	if utf8.RuneCountInString(embed.Title) > MAX_DISCORD_TITLE {
+		t.Errorf("title exceed limit: %d", utf8.RuneCountInString(embed.Title))
+	}
+	if utf8.RuneCountInString(embed.Description) > MAX_DISCORD_DESCRIPTION {
+		t.Errorf("description exceed limit: %d", utf8.RuneCountInString(embed.Description))
+	}
+	if len(embed.Fields) > MAX_DISCORD_FIELDS {
+		t.Errorf("fields exceed limit: %d", len(embed.Fields))
+	}
+
+	total := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	for _, field := range embed.Fields {
+		if "" == field.Value {
+			t.Errorf("field %s value should not be empty", field.Name)
+		}
+		if utf8.RuneCountInString(field.Value) > MAX_DISCORD_FIELD_VALUE {
+			t.Errorf("field %s value exceed limit: %d", field.Name, utf8.RuneCountInString(field.Value))
+		}
+		total += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	if total > MAX_DISCORD_EMBED {
+		t.Errorf("embed exceed total limit: %d", total)
+	}
+}
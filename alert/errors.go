@@ -0,0 +1,51 @@
+package alert
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+const (
+	MAX_PENDING_ERRORS = 10
+)
+
+// Errors collect asynchronous delivery errors, alert cores return them from the next Write or Sync so they are
+// written to zap ErrorOutput, only the first MAX_PENDING_ERRORS errors are kept
+type Errors struct {
+	lock    sync.Mutex
+	errs    []error
+	omitted int
+}
+
+// Add collect delivery error, nil error is ignored
+func (e *Errors) Add(err error) {
+	if nil == err {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if len(e.errs) >= MAX_PENDING_ERRORS {
+		e.omitted++
+		return
+	}
+	e.errs = append(e.errs, err)
+}
+
+// Take get collected errors as one error and reset the collection
+func (e *Errors) Take() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	err := multierr.Combine(e.errs...)
+	if e.omitted > 0 {
+		err = multierr.Append(err, fmt.Errorf("%d more delivery errors omitted", e.omitted))
+	}
+	e.errs = nil
+	e.omitted = 0
+
+	return err
+}
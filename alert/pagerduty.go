@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	PAGERDUTY_EVENTS_URL  = "https://events.pagerduty.com/v2/enqueue"
+	MAX_PAGERDUTY_SUMMARY = 1024
+)
+
+var pagerDutySeverity = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "info",
+	zapcore.InfoLevel:   "info",
+	zapcore.WarnLevel:   "warning",
+	zapcore.ErrorLevel:  "error",
+	zapcore.DPanicLevel: "critical",
+	zapcore.PanicLevel:  "critical",
+	zapcore.FatalLevel:  "critical",
+}
+
+type PagerDutyOptions struct {
+	RoutingKey string
+	Source     string // default to serviceName field
+	URL        string // default to PAGERDUTY_EVENTS_URL
+	Client     *http.Client
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp"`
+	Component     string                 `json:"component,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// PagerDutySink trigger pagerduty events v2 incident using alert fingerprint as dedup key, resolved alert resolve
+// the incident
+func PagerDutySink(options PagerDutyOptions) Sink {
+	if "" == options.URL {
+		options.URL = PAGERDUTY_EVENTS_URL
+	}
+	client := newHTTPClient(options.Client)
+
+	return SinkFunc(func(a Alert) error {
+		event := pagerDutyEvent{
+			RoutingKey:  options.RoutingKey,
+			EventAction: "trigger",
+			DedupKey:    a.Fingerprint,
+		}
+
+		if a.Resolved {
+			event.EventAction = "resolve"
+			return postJSON(client, options.URL, event, nil)
+		}
+
+		source := options.Source
+		if serviceName, ok := a.Fields["serviceName"].(string); ok && "" == source {
+			source = serviceName
+		}
+		if "" == source {
+			source = "unknown"
+		}
+
+		details := map[string]interface{}{}
+		for k, v := range a.Fields {
+			details[k] = v
+		}
+		if "" != a.Entry.Stack {
+			details["stacktrace"] = a.Entry.Stack
+		}
+
+		event.Payload = &pagerDutyPayload{
+			Summary:       truncate(a.Entry.Message, MAX_PAGERDUTY_SUMMARY),
+			Source:        source,
+			Severity:      pagerDutySeverity[a.Entry.Level],
+			Timestamp:     a.Entry.Time.Format(time.RFC3339),
+			Component:     a.Entry.LoggerName,
+			CustomDetails: details,
+		}
+
+		return postJSON(client, options.URL, event, nil)
+	})
+}
@@ -0,0 +1,64 @@
+package alert
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPagerDutySink(t *testing.T) {
+	c := newCapture(http.StatusAccepted, nil)
+	defer c.Close()
+
+	sink := PagerDutySink(PagerDutyOptions{RoutingKey: "key", URL: c.URL})
+	if err := sink.Send(testAlert()); nil != err {
+		t.Fatal(err)
+	}
+
+	var event pagerDutyEvent
+	c.last(t, &event)
+	if "key" != event.RoutingKey || "trigger" != event.EventAction || "abc" != event.DedupKey {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if "payment failed" != event.Payload.Summary || "payment" != event.Payload.Source || "error" != event.Payload.Severity {
+		t.Errorf("unexpected payload: %+v", event.Payload)
+	}
+	if nil == event.Payload.CustomDetails["stacktrace"] {
+		t.Error("stack should be sent as custom detail")
+	}
+}
+
+func TestPagerDutySinkResolved(t *testing.T) {
+	c := newCapture(http.StatusAccepted, nil)
+	defer c.Close()
+
+	a := testAlert()
+	a.Resolved = true
+	if err := PagerDutySink(PagerDutyOptions{RoutingKey: "key", URL: c.URL}).Send(a); nil != err {
+		t.Fatal(err)
+	}
+
+	var event pagerDutyEvent
+	c.last(t, &event)
+	if "resolve" != event.EventAction || "abc" != event.DedupKey || nil != event.Payload {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestPagerDutySinkSummaryLimit(t *testing.T) {
+	c := newCapture(http.StatusAccepted, nil)
+	defer c.Close()
+
+	a := testAlert()
+	a.Entry.Message = strings.Repeat("m", 2000)
+	if err := PagerDutySink(PagerDutyOptions{RoutingKey: "key", URL: c.URL}).Send(a); nil != err {
+		t.Fatal(err)
+	}
+
+	var event pagerDutyEvent
+	c.last(t, &event)
+	if n := utf8.RuneCountInString(event.Payload.Summary); MAX_PAGERDUTY_SUMMARY != n {
+		t.Errorf("summary should be truncated to %d characters, got %d", MAX_PAGERDUTY_SUMMARY, n)
+	}
+}
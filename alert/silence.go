@@ -0,0 +1,367 @@
+package alert
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	DEFAULT_SILENCE_REFRESH = 10 * time.Second
+)
+
+// Silence suppress matched alerts between StartsAt and EndsAt, an alert is matched when every non empty matcher
+// is matched, a silence without matcher is a maintenance window that suppress every alert
+// - Fingerprint: alert fingerprint equality
+// - Levels: entry level is one of the levels
+// - Message: pattern for entry message
+// - Fields: field value equality, ex: {"component": "payment"}
+type Silence struct {
+	ID          string            `json:"id"`
+	Fingerprint string            `json:"fingerprint,omitempty"`
+	Levels      []zapcore.Level   `json:"levels,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	CreatedBy   string            `json:"createdBy,omitempty"`
+	Comment     string            `json:"comment,omitempty"`
+
+	message *regexp.Regexp
+}
+
+// SilenceReport is number of alerts suppressed by an ended silence
+type SilenceReport struct {
+	Silence    Silence
+	Suppressed int
+}
+
+// Active check whether the silence is in effect at the given time
+func (s Silence) Active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Match check whether the silence suppress the alert
+func (s Silence) Match(a Alert) bool {
+	if "" != s.Fingerprint && s.Fingerprint != a.Fingerprint {
+		return false
+	}
+	if len(s.Levels) > 0 && !containsLevel(s.Levels, a.Entry.Level) {
+		return false
+	}
+	if "" != s.Message {
+		message := s.message
+		if nil == message {
+			var err error
+			if message, err = regexp.Compile(s.Message); nil != err {
+				return false
+			}
+		}
+		if !message.MatchString(a.Entry.Message) {
+			return false
+		}
+	}
+	for key, expected := range s.Fields {
+		value, ok := a.Fields[key]
+		if !ok || fmt.Sprint(value) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Silence) compile() error {
+	if "" == s.Message {
+		return nil
+	}
+	message, err := regexp.Compile(s.Message)
+	if nil != err {
+		return err
+	}
+	s.message = message
+	return nil
+}
+
+func containsLevel(levels []zapcore.Level, level zapcore.Level) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// SilenceStore persist silences, List should return every silence that is not expired yet
+type SilenceStore interface {
+	Save(s Silence) error
+	List() ([]Silence, error)
+}
+
+type memorySilenceStore struct {
+	lock     sync.Mutex
+	silences map[string]Silence
+}
+
+// NewMemorySilenceStore create in process silence store
+func NewMemorySilenceStore() SilenceStore {
+	return &memorySilenceStore{
+		silences: map[string]Silence{},
+	}
+}
+
+func (s *memorySilenceStore) Save(silence Silence) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.silences[silence.ID] = silence
+	return nil
+}
+
+func (s *memorySilenceStore) List() ([]Silence, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	silences := make([]Silence, 0, len(s.silences))
+	for id, silence := range s.silences {
+		if !now.Before(silence.EndsAt) {
+			delete(s.silences, id)
+			continue
+		}
+		silences = append(silences, silence)
+	}
+	return silences, nil
+}
+
+type suppression struct {
+	silence Silence
+	count   int
+}
+
+// Silences is silence registry checked by alert cores before sending an alert, silences are cached for
+// DEFAULT_SILENCE_REFRESH so a shared store is not hit on every alert
+type Silences struct {
+	store SilenceStore
+
+	lock       sync.Mutex
+	cached     []Silence
+	refreshAt  time.Time
+	refreshing bool
+	version    int
+}
+
+// NewSilences create silence registry, default to memory store if store is nil
+func NewSilences(store SilenceStore) *Silences {
+	if nil == store {
+		store = NewMemorySilenceStore()
+	}
+	return &Silences{
+		store: store,
+	}
+}
+
+// Add save a silence, ID is generated if empty and StartsAt default to now
+func (s *Silences) Add(silence Silence) (Silence, error) {
+	if "" == silence.ID {
+		silence.ID = newSilenceID()
+	}
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = time.Now()
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return silence, errors.New("silence must end after it starts")
+	}
+	if err := silence.compile(); nil != err {
+		return silence, fmt.Errorf("invalid message pattern: %w", err)
+	}
+
+	if err := s.store.Save(silence); nil != err {
+		return silence, err
+	}
+	s.invalidate()
+
+	return silence, nil
+}
+
+// Expire end the silence immediately, return false if the silence is not found or already ended
+func (s *Silences) Expire(id string) (bool, error) {
+	silences, err := s.store.List()
+	if nil != err {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, silence := range silences {
+		if id != silence.ID || !now.Before(silence.EndsAt) {
+			continue
+		}
+		silence.EndsAt = now
+		if silence.StartsAt.After(now) {
+			silence.StartsAt = now
+		}
+		if err := s.store.Save(silence); nil != err {
+			return false, err
+		}
+		s.invalidate()
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// List get every silence that is not expired yet
+func (s *Silences) List() ([]Silence, error) {
+	return s.store.List()
+}
+
+// Silenced check whether the alert is suppressed by an active silence, a nil registry silence nothing
+func (s *Silences) Silenced(a Alert, now time.Time) bool {
+	return len(s.match(a, now)) > 0
+}
+
+// NewCounter create suppressed alert counter, every alert core sharing the registry should have its own counter
+// so each core report its own suppressed count, a nil registry return nil counter that silence nothing
+func (s *Silences) NewCounter() *SilenceCounter {
+	if nil == s {
+		return nil
+	}
+	return &SilenceCounter{
+		silences:   s,
+		suppressed: map[string]*suppression{},
+	}
+}
+
+func (s *Silences) match(a Alert, now time.Time) []Silence {
+	if nil == s {
+		return nil
+	}
+
+	var matched []Silence
+	for _, silence := range s.silences(now) {
+		if silence.Active(now) && silence.Match(a) {
+			matched = append(matched, silence)
+		}
+	}
+	return matched
+}
+
+func (s *Silences) invalidate() {
+	s.lock.Lock()
+	s.refreshAt = time.Time{}
+	s.version++
+	s.lock.Unlock()
+}
+
+// silences get cached silences, the store is listed without holding the lock so a slow store doesn't block other
+// alerts, they use the stale silences meanwhile
+func (s *Silences) silences(now time.Time) []Silence {
+	s.lock.Lock()
+	if now.Before(s.refreshAt) || s.refreshing {
+		cached := s.cached
+		s.lock.Unlock()
+		return cached
+	}
+	s.refreshing = true
+	version := s.version
+	s.lock.Unlock()
+
+	silences, err := s.store.List()
+	for i := range silences {
+		_ = silences[i].compile()
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.refreshing = false
+	// keep the stale silences when the store is unavailable
+	if nil == err {
+		s.cached = silences
+	}
+	// registry changed while listing, refresh again on the next call
+	if version == s.version {
+		s.refreshAt = now.Add(DEFAULT_SILENCE_REFRESH)
+	}
+
+	return s.cached
+}
+
+// SilenceCounter count alerts suppressed by silences of a registry, see Silences.NewCounter
+type SilenceCounter struct {
+	silences *Silences
+
+	lock       sync.Mutex
+	suppressed map[string]*suppression
+}
+
+// Silenced check whether the alert is suppressed by an active silence and count it, a nil counter silence nothing
+func (c *SilenceCounter) Silenced(a Alert, now time.Time) bool {
+	if nil == c {
+		return false
+	}
+
+	matched := c.silences.match(a, now)
+	if 0 == len(matched) {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, silence := range matched {
+		if _, ok := c.suppressed[silence.ID]; !ok {
+			c.suppressed[silence.ID] = &suppression{silence: silence}
+		}
+		c.suppressed[silence.ID].count++
+	}
+	return true
+}
+
+// Ended get suppressed alert count of silences ended at the given time, each silence is only reported once,
+// silences without suppressed alert are not reported
+func (c *SilenceCounter) Ended(now time.Time) []SilenceReport {
+	if nil == c {
+		return nil
+	}
+
+	silences := c.silences.silences(now)
+	listed := make(map[string]Silence, len(silences))
+	for _, silence := range silences {
+		listed[silence.ID] = silence
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var reports []SilenceReport
+	for id, sp := range c.suppressed {
+		if silence, ok := listed[id]; ok {
+			sp.silence = silence
+		} else if now.Before(sp.silence.EndsAt) {
+			// silence removed from the store has been ended
+			sp.silence.EndsAt = now
+		}
+
+		if now.Before(sp.silence.EndsAt) {
+			continue
+		}
+		reports = append(reports, SilenceReport{
+			Silence:    sp.silence,
+			Suppressed: sp.count,
+		})
+		delete(c.suppressed, id)
+	}
+	return reports
+}
+
+func newSilenceID() string {
+	by := make([]byte, 8)
+	_, _ = rand.Read(by)
+	return hex.EncodeToString(by)
+}
@@ -0,0 +1,96 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/payfazz/fz-sentry/httperror"
+	"github.com/payfazz/fz-sentry/loghttp"
+)
+
+const (
+	SILENCE_PATH = "/debug/silences"
+)
+
+type silencePayload struct {
+	Silence
+	Duration string `json:"duration,omitempty"`
+}
+
+type silenceHandler struct {
+	silences *Silences
+}
+
+// SilenceHandler create admin http handler to list (GET), create (POST) and expire (DELETE ?id=) silences,
+// POST payload example: {"levels": ["error"], "message": "^migration", "fields": {"component": "payment"},
+// "startsAt": "2021-01-01T22:00:00+07:00", "duration": "2h", "createdBy": "ops", "comment": "db migration"},
+// endsAt can be given instead of duration
+func SilenceHandler(silences *Silences) http.Handler {
+	return &silenceHandler{
+		silences: silences,
+	}
+}
+
+func (h *silenceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		silences, err := h.silences.List()
+		if nil != err {
+			loghttp.ErrorWithRequest(w, r, httperror.InternalServer(err))
+			return
+		}
+		loghttp.Write(w, silences, http.StatusOK)
+	case http.MethodPost:
+		var payload silencePayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); nil != err {
+			loghttp.ErrorWithRequest(w, r, httperror.BadRequest(err))
+			return
+		}
+
+		silence := payload.Silence
+		if "" != payload.Duration {
+			duration, err := time.ParseDuration(payload.Duration)
+			if nil != err || duration <= 0 {
+				loghttp.ErrorWithRequest(w, r, httperror.BadRequest(fmt.Errorf("invalid duration: %s", payload.Duration)))
+				return
+			}
+			if silence.StartsAt.IsZero() {
+				silence.StartsAt = time.Now()
+			}
+			silence.EndsAt = silence.StartsAt.Add(duration)
+		}
+		if silence.EndsAt.IsZero() {
+			loghttp.ErrorWithRequest(w, r, httperror.BadRequest(errors.New("endsAt or duration is required")))
+			return
+		}
+
+		silence, err := h.silences.Add(silence)
+		if nil != err {
+			loghttp.ErrorWithRequest(w, r, httperror.BadRequest(err))
+			return
+		}
+		loghttp.Write(w, silence, http.StatusCreated)
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if "" == id {
+			loghttp.ErrorWithRequest(w, r, httperror.BadRequest(errors.New("id is required")))
+			return
+		}
+
+		found, err := h.silences.Expire(id)
+		if nil != err {
+			loghttp.ErrorWithRequest(w, r, httperror.InternalServer(err))
+			return
+		}
+		if !found {
+			loghttp.ErrorWithRequest(w, r, httperror.NotFound(fmt.Errorf("silence %s is not found", id)))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		loghttp.ErrorWithRequest(w, r, httperror.MethodNotAllowed(fmt.Errorf("method %s is not allowed", r.Method)))
+	}
+}
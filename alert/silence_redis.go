@@ -0,0 +1,61 @@
+package alert
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	DEFAULT_SILENCE_REDIS_KEY = "fz-sentry:silences"
+)
+
+type redisSilenceStore struct {
+	client redis.Cmdable
+	key    string
+}
+
+// NewRedisSilenceStore create silence store shared between replicas, silences are saved as a hash on the given key
+func NewRedisSilenceStore(client redis.Cmdable, key string) SilenceStore {
+	if "" == key {
+		key = DEFAULT_SILENCE_REDIS_KEY
+	}
+	return &redisSilenceStore{
+		client: client,
+		key:    key,
+	}
+}
+
+func (s *redisSilenceStore) Save(silence Silence) error {
+	by, err := json.Marshal(silence)
+	if nil != err {
+		return err
+	}
+	return s.client.HSet(s.key, silence.ID, by).Err()
+}
+
+func (s *redisSilenceStore) List() ([]Silence, error) {
+	values, err := s.client.HGetAll(s.key).Result()
+	if nil != err {
+		return nil, err
+	}
+
+	now := time.Now()
+	silences := make([]Silence, 0, len(values))
+	var expired []string
+	for id, value := range values {
+		var silence Silence
+		if err := json.Unmarshal([]byte(value), &silence); nil != err || !now.Before(silence.EndsAt) {
+			expired = append(expired, id)
+			continue
+		}
+		silences = append(silences, silence)
+	}
+
+	if len(expired) > 0 {
+		s.client.HDel(s.key, expired...)
+	}
+
+	return silences, nil
+}
@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// blockingStore block List until release is closed
+type blockingStore struct {
+	SilenceStore
+	listing chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStore) List() ([]Silence, error) {
+	s.listing <- struct{}{}
+	<-s.release
+	return s.SilenceStore.List()
+}
+
+func silenceAlert(message string) Alert {
+	return Alert{Entry: zapcore.Entry{Level: zapcore.ErrorLevel, Message: message}, Fingerprint: message}
+}
+
+func TestSilenceCounterShared(t *testing.T) {
+	silences := NewSilences(nil)
+	silence, err := silences.Add(Silence{Fingerprint: "boom", EndsAt: time.Now().Add(time.Hour)})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	first, second := silences.NewCounter(), silences.NewCounter()
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if !first.Silenced(silenceAlert("boom"), now) {
+			t.Fatal("alert should be silenced")
+		}
+	}
+	if !second.Silenced(silenceAlert("boom"), now) || second.Silenced(silenceAlert("other"), now) {
+		t.Fatal("only matched alert should be silenced")
+	}
+
+	if reports := first.Ended(now); 0 != len(reports) {
+		t.Fatalf("active silence should not be reported, got %v", reports)
+	}
+	if _, err := silences.Expire(silence.ID); nil != err {
+		t.Fatal(err)
+	}
+
+	ended := time.Now().Add(time.Second)
+	reports := first.Ended(ended)
+	if 1 != len(reports) || 3 != reports[0].Suppressed {
+		t.Errorf("first counter should report its own count, got %v", reports)
+	}
+	reports = second.Ended(ended)
+	if 1 != len(reports) || 1 != reports[0].Suppressed {
+		t.Errorf("second counter should report its own count, got %v", reports)
+	}
+	if reports := first.Ended(ended); 0 != len(reports) {
+		t.Errorf("ended silence should only be reported once, got %v", reports)
+	}
+}
+
+func TestSilencesRefreshOutsideLock(t *testing.T) {
+	store := &blockingStore{
+		SilenceStore: NewMemorySilenceStore(),
+		listing:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	silences := NewSilences(store)
+
+	done := make(chan bool)
+	go func() {
+		done <- silences.Silenced(silenceAlert("boom"), time.Now())
+	}()
+	<-store.listing
+
+	// the registry is refreshing, other alerts use the stale silences instead of waiting the store
+	checked := make(chan bool)
+	go func() {
+		checked <- silences.Silenced(silenceAlert("boom"), time.Now())
+	}()
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("Silenced should not wait for the store while it is refreshing")
+	}
+
+	close(store.release)
+	<-done
+}
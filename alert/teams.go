@@ -0,0 +1,72 @@
+package alert
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var teamsColor = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "9B30FF",
+	zapcore.InfoLevel:   "2EB886",
+	zapcore.WarnLevel:   "DAA038",
+	zapcore.ErrorLevel:  "A30200",
+	zapcore.DPanicLevel: "A30200",
+	zapcore.PanicLevel:  "A30200",
+	zapcore.FatalLevel:  "A30200",
+}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type teamsSection struct {
+	ActivityTitle    string      `json:"activityTitle,omitempty"`
+	ActivitySubtitle string      `json:"activitySubtitle,omitempty"`
+	Facts            []teamsFact `json:"facts,omitempty"`
+	Text             string      `json:"text,omitempty"`
+}
+
+type teamsMessage struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Title      string         `json:"title"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+// TeamsSink send alert as message card to microsoft teams incoming webhook, http client is optional
+func TeamsSink(webhookURL string, client *http.Client) Sink {
+	client = newHTTPClient(client)
+
+	return SinkFunc(func(a Alert) error {
+		title := a.Entry.Message
+		if a.Resolved {
+			title = "[RESOLVED] " + title
+		}
+
+		section := teamsSection{
+			ActivityTitle:    a.Entry.Level.CapitalString(),
+			ActivitySubtitle: a.Entry.Time.Format(time.RFC3339),
+		}
+		for _, key := range a.SortedKeys() {
+			section.Facts = append(section.Facts, teamsFact{Name: key, Value: fmt.Sprint(a.Fields[key])})
+		}
+		if "" != a.Entry.Stack {
+			section.Text = "<pre>" + a.Entry.Stack + "</pre>"
+		}
+
+		return postJSON(client, webhookURL, teamsMessage{
+			Type:       "MessageCard",
+			Context:    "http://schema.org/extensions",
+			ThemeColor: teamsColor[a.Entry.Level],
+			Summary:    title,
+			Title:      title,
+			Sections:   []teamsSection{section},
+		}, nil)
+	})
+}
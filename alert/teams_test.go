@@ -0,0 +1,51 @@
+package alert
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTeamsSink(t *testing.T) {
+	c := newCapture(http.StatusOK, nil)
+	defer c.Close()
+
+	if err := TeamsSink(c.URL, nil).Send(testAlert()); nil != err {
+		t.Fatal(err)
+	}
+
+	var msg teamsMessage
+	c.last(t, &msg)
+	if "payment failed" != msg.Title || "A30200" != msg.ThemeColor || 1 != len(msg.Sections) {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if 2 != len(msg.Sections[0].Facts) || "requestId" != msg.Sections[0].Facts[0].Name {
+		t.Errorf("fields should be sent as sorted facts, got %+v", msg.Sections[0].Facts)
+	}
+}
+
+func TestTeamsSinkResolved(t *testing.T) {
+	c := newCapture(http.StatusOK, nil)
+	defer c.Close()
+
+	a := testAlert()
+	a.Resolved = true
+	if err := TeamsSink(c.URL, nil).Send(a); nil != err {
+		t.Fatal(err)
+	}
+
+	var msg teamsMessage
+	c.last(t, &msg)
+	if "[RESOLVED] payment failed" != msg.Title {
+		t.Errorf("unexpected title: %s", msg.Title)
+	}
+}
+
+func TestTeamsSinkError(t *testing.T) {
+	c := newCapture(http.StatusBadGateway, nil)
+	defer c.Close()
+
+	err := TeamsSink(c.URL, nil).Send(testAlert())
+	if statusError, ok := err.(*StatusError); !ok || http.StatusBadGateway != statusError.Code {
+		t.Errorf("expected 502 StatusError, got %v", err)
+	}
+}
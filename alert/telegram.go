@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	TELEGRAM_API_URL  = "https://api.telegram.org"
+	MAX_TELEGRAM_TEXT = 4096 // characters after entities parsing
+)
+
+type TelegramOptions struct {
+	Token   string
+	ChatID  string
+	BaseURL string // default to TELEGRAM_API_URL
+	Client  *http.Client
+}
+
+type telegramMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+// TelegramSink send alert using telegram bot api sendMessage
+func TelegramSink(options TelegramOptions) Sink {
+	if "" == options.BaseURL {
+		options.BaseURL = TELEGRAM_API_URL
+	}
+	client := newHTTPClient(options.Client)
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimRight(options.BaseURL, "/"), options.Token)
+
+	return SinkFunc(func(a Alert) error {
+		text := &telegramText{remaining: MAX_TELEGRAM_TEXT}
+		if a.Resolved {
+			text.add("<b>", "[RESOLVED] ", "</b>")
+		}
+		text.add("<b>", fmt.Sprintf("[%s] %s", a.Entry.Level.CapitalString(), a.Entry.Message), "</b>")
+		text.add("", "\n", "")
+		for _, key := range a.SortedKeys() {
+			text.add("<b>", key, "</b>")
+			text.add("", fmt.Sprintf(": %v\n", a.Fields[key]), "")
+		}
+		if "" != a.Entry.Stack {
+			text.add("<pre>", a.Entry.Stack, "</pre>")
+		}
+
+		err := postJSON(client, endpoint, telegramMessage{
+			ChatID:    options.ChatID,
+			Text:      text.String(),
+			ParseMode: "HTML",
+		}, nil)
+
+		// transport error contain the request url, drop it so the bot token is not written to the error output
+		if urlError, ok := err.(*url.Error); ok {
+			return fmt.Errorf("telegram sendMessage: %w", urlError.Err)
+		}
+		return err
+	})
+}
+
+// telegramText build html message text within MAX_TELEGRAM_TEXT visible characters
+type telegramText struct {
+	strings.Builder
+	remaining int
+}
+
+// add append escaped content wrapped with html tags, content is truncated to the remaining characters
+func (t *telegramText) add(open string, content string, close string) {
+	if t.remaining <= 0 {
+		return
+	}
+	content = truncate(content, t.remaining)
+	t.remaining -= utf8.RuneCountInString(content)
+	t.WriteString(open + html.EscapeString(content) + close)
+}
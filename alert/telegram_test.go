@@ -0,0 +1,75 @@
+package alert
+
+import (
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var htmlTag = regexp.MustCompile(`</?(b|pre)>`)
+
+func TestTelegramSink(t *testing.T) {
+	c := newCapture(http.StatusOK, nil)
+	defer c.Close()
+
+	a := testAlert()
+	a.Entry.Message = "amount < 0"
+	sink := TelegramSink(TelegramOptions{Token: "TOKEN", ChatID: "42", BaseURL: c.URL})
+	if err := sink.Send(a); nil != err {
+		t.Fatal(err)
+	}
+
+	var msg telegramMessage
+	req := c.last(t, &msg)
+	if "/botTOKEN/sendMessage" != req.Path {
+		t.Errorf("unexpected path: %s", req.Path)
+	}
+	if "42" != msg.ChatID || "HTML" != msg.ParseMode {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if !strings.Contains(msg.Text, "<b>[ERROR] amount &lt; 0</b>") {
+		t.Errorf("message should be escaped, got %s", msg.Text)
+	}
+	if !strings.Contains(msg.Text, "<b>requestId</b>: req-1") || !strings.Contains(msg.Text, "<pre>main.main") {
+		t.Errorf("fields and stack should be sent, got %s", msg.Text)
+	}
+}
+
+func TestTelegramSinkLimit(t *testing.T) {
+	c := newCapture(http.StatusOK, nil)
+	defer c.Close()
+
+	a := testAlert()
+	a.Fields["payload"] = strings.Repeat("<&>", 2000)
+	a.Entry.Stack = strings.Repeat("s", 5000)
+	if err := TelegramSink(TelegramOptions{Token: "TOKEN", ChatID: "42", BaseURL: c.URL}).Send(a); nil != err {
+		t.Fatal(err)
+	}
+
+	var msg telegramMessage
+	c.last(t, &msg)
+
+	visible := html.UnescapeString(htmlTag.ReplaceAllString(msg.Text, ""))
+	if n := utf8.RuneCountInString(visible); n > MAX_TELEGRAM_TEXT {
+		t.Errorf("text exceed limit: %d", n)
+	}
+	if strings.Count(msg.Text, "<b>") != strings.Count(msg.Text, "</b>") {
+		t.Errorf("truncation should keep html tags balanced")
+	}
+}
+
+func TestTelegramSinkTransportError(t *testing.T) {
+	c := newCapture(http.StatusOK, nil)
+	c.Close()
+
+	err := TelegramSink(TelegramOptions{Token: "123:SECRET", ChatID: "42", BaseURL: c.URL}).Send(testAlert())
+	if nil == err {
+		t.Fatal("expected transport error")
+	}
+	if strings.Contains(err.Error(), "SECRET") {
+		t.Errorf("error should not contain the bot token: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+type WebhookOptions struct {
+	URL     string
+	Method  string             // default to POST
+	Headers map[string]string  // additional request headers
+	Body    *template.Template // request body template executed with WebhookData, default to WebhookData as JSON
+	Client  *http.Client
+}
+
+// WebhookData is the data passed to webhook body template
+type WebhookData struct {
+	Level       string                 `json:"level"`
+	Message     string                 `json:"message"`
+	Time        time.Time              `json:"time"`
+	Logger      string                 `json:"logger,omitempty"`
+	Caller      string                 `json:"caller,omitempty"`
+	Stack       string                 `json:"stack,omitempty"`
+	Fields      map[string]interface{} `json:"fields"`
+	Fingerprint string                 `json:"fingerprint"`
+	Resolved    bool                   `json:"resolved"`
+}
+
+// WebhookFuncs is template functions available for webhook body template, `json` marshal value to JSON
+var WebhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		by, err := json.Marshal(v)
+		return string(by), err
+	},
+}
+
+// NewWebhookTemplate parse webhook body template with WebhookFuncs,
+// ex: `{"text": {{ json .Message }}, "requestId": {{ json (index .Fields "requestId") }}}`
+func NewWebhookTemplate(body string) (*template.Template, error) {
+	return template.New("webhook").Funcs(WebhookFuncs).Parse(body)
+}
+
+// WebhookSink send alert to generic http endpoint
+func WebhookSink(options WebhookOptions) Sink {
+	if "" == options.Method {
+		options.Method = http.MethodPost
+	}
+	client := newHTTPClient(options.Client)
+
+	return SinkFunc(func(a Alert) error {
+		data := WebhookData{
+			Level:       a.Entry.Level.String(),
+			Message:     a.Entry.Message,
+			Time:        a.Entry.Time,
+			Logger:      a.Entry.LoggerName,
+			Stack:       a.Entry.Stack,
+			Fields:      a.Fields,
+			Fingerprint: a.Fingerprint,
+			Resolved:    a.Resolved,
+		}
+		if a.Entry.Caller.Defined {
+			data.Caller = a.Entry.Caller.TrimmedPath()
+		}
+
+		var body []byte
+		if nil == options.Body {
+			by, err := json.Marshal(data)
+			if nil != err {
+				return err
+			}
+			body = by
+		}
+		if nil != options.Body {
+			buf := &bytes.Buffer{}
+			if err := options.Body.Execute(buf, data); nil != err {
+				return err
+			}
+			body = buf.Bytes()
+		}
+
+		return post(client, options.Method, options.URL, body, "application/json", options.Headers)
+	})
+}
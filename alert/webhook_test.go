@@ -0,0 +1,44 @@
+package alert
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWebhookSink(t *testing.T) {
+	c := newCapture(http.StatusOK, nil)
+	defer c.Close()
+
+	sink := WebhookSink(WebhookOptions{URL: c.URL, Headers: map[string]string{"Authorization": "Bearer x"}})
+	if err := sink.Send(testAlert()); nil != err {
+		t.Fatal(err)
+	}
+
+	var data WebhookData
+	req := c.last(t, &data)
+	if http.MethodPost != req.Method || "Bearer x" != req.Header.Get("Authorization") {
+		t.Errorf("unexpected request: %s %v", req.Method, req.Header)
+	}
+	if "error" != data.Level || "payment failed" != data.Message || "abc" != data.Fingerprint || "req-1" != data.Fields["requestId"] {
+		t.Errorf("unexpected data: %+v", data)
+	}
+}
+
+func TestWebhookSinkTemplate(t *testing.T) {
+	c := newCapture(http.StatusOK, nil)
+	defer c.Close()
+
+	body, err := NewWebhookTemplate(`{"text": {{ json .Message }}, "requestId": {{ json (index .Fields "requestId") }}}`)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if err := WebhookSink(WebhookOptions{URL: c.URL, Method: http.MethodPut, Body: body}).Send(testAlert()); nil != err {
+		t.Fatal(err)
+	}
+
+	var payload map[string]string
+	req := c.last(t, &payload)
+	if http.MethodPut != req.Method || "payment failed" != payload["text"] || "req-1" != payload["requestId"] {
+		t.Errorf("unexpected request: %s %v", req.Method, payload)
+	}
+}
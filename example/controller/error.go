@@ -23,6 +23,6 @@ func Error() http.HandlerFunc {
 			zap.String("cause", err.Error()),
 		)
 
-		loghttp.Error(w, err)
+		loghttp.ErrorWithRequest(w, r, err)
 	}
 }
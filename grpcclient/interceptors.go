@@ -0,0 +1,70 @@
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/payfazz/fz-sentry/logger"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type ClientInterceptorsOptions struct {
+	Logger                       *zap.Logger
+	WithPayload                  bool
+	WithPrometheus               bool
+	WithOpenTelemetry            bool
+	WithUnaryTimeout             time.Duration
+	AdditionalUnaryInterceptors  []grpc.UnaryClientInterceptor
+	AdditionalStreamInterceptors []grpc.StreamClientInterceptor
+}
+
+func DialOptions(options ClientInterceptorsOptions) []grpc.DialOption {
+	var unaryInterceptors []grpc.UnaryClientInterceptor
+	var streamInterceptors []grpc.StreamClientInterceptor
+
+	if options.WithUnaryTimeout > 0 {
+		unaryInterceptors = append(unaryInterceptors, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			if _, ok := ctx.Deadline(); ok {
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}
+			newCtx, cancel := context.WithTimeout(ctx, options.WithUnaryTimeout)
+			defer cancel()
+			return invoker(newCtx, method, req, reply, cc, opts...)
+		})
+	}
+
+	if options.WithOpenTelemetry {
+		unaryInterceptors = append(unaryInterceptors, otelgrpc.UnaryClientInterceptor())
+		streamInterceptors = append(streamInterceptors, otelgrpc.StreamClientInterceptor())
+	}
+
+	unaryInterceptors = append(unaryInterceptors,
+		logger.GrpcUnaryClientInterceptor(options.Logger),
+		logger.GrpcEndpointUnaryClientInterceptor(),
+	)
+
+	streamInterceptors = append(streamInterceptors,
+		logger.GrpcStreamClientInterceptor(options.Logger),
+		logger.GrpcEndpointStreamClientInterceptor(),
+	)
+
+	if options.WithPayload {
+		unaryInterceptors = append(unaryInterceptors, logger.GrpcPayloadUnaryClientInterceptor())
+	}
+
+	if options.WithPrometheus {
+		unaryInterceptors = append(unaryInterceptors, grpc_prometheus.UnaryClientInterceptor)
+		streamInterceptors = append(streamInterceptors, grpc_prometheus.StreamClientInterceptor)
+	}
+
+	unaryInterceptors = append(unaryInterceptors, options.AdditionalUnaryInterceptors...)
+	streamInterceptors = append(streamInterceptors, options.AdditionalStreamInterceptors...)
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(streamInterceptors...),
+	}
+}
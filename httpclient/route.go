@@ -0,0 +1,29 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	UNKNOWN_ROUTE = "unknown"
+)
+
+type routeKeyType struct{}
+
+var routeKey routeKeyType
+
+// WithRoute set upstream route pattern for outgoing request, used as log field and metric label instead of the
+// requested url, ex: `/v1/users/:id`
+func WithRoute(req *http.Request, route string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), routeKey, route))
+}
+
+// GetRoute get upstream route pattern set by WithRoute, will return UNKNOWN_ROUTE if the route is not set so the
+// metric label cardinality is bounded
+func GetRoute(req *http.Request) string {
+	if route, ok := req.Context().Value(routeKey).(string); ok && "" != route {
+		return route
+	}
+	return UNKNOWN_ROUTE
+}
@@ -0,0 +1,131 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/payfazz/fz-sentry/logger"
+	"github.com/payfazz/fz-sentry/monitor/prometheusclient"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+const (
+	TRANSPORT_ERROR = "error"
+)
+
+type TransportOptions struct {
+	Logger         *zap.Logger // used when request context doesn't have request scoped logger
+	Upstream       string      // upstream name used in log and metric label, default to request host
+	WithPayload    bool
+	WithPrometheus bool
+}
+
+type transport struct {
+	base    http.RoundTripper
+	options TransportOptions
+}
+
+// NewTransport wrap base round tripper to log outgoing request using request scoped logger and propagate request id
+// and trace context to upstream, http.DefaultTransport will be used if base is nil
+func NewTransport(base http.RoundTripper, options TransportOptions) http.RoundTripper {
+	if nil == base {
+		base = http.DefaultTransport
+	}
+
+	return &transport{
+		base:    base,
+		options: options,
+	}
+}
+
+// NewClient create http client using NewTransport
+func NewClient(options TransportOptions) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(nil, options),
+	}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	log := t.getLogger(ctx)
+
+	req = req.Clone(ctx)
+	if header, requestId := logger.RequestIDHeader(), logger.RequestID(ctx); "" != header && "" != requestId && "" == req.Header.Get(header) {
+		req.Header.Set(header, requestId)
+	}
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	upstream := t.options.Upstream
+	if "" == upstream {
+		upstream = req.URL.Host
+	}
+	route := GetRoute(req)
+
+	fields := []zap.Field{
+		zap.String("upstream", upstream),
+		zap.String("host", req.URL.Host),
+		zap.String("method", req.Method),
+		zap.String("route", route),
+		zap.String("path", req.URL.Path),
+	}
+
+	log.Info(fmt.Sprintf("begin http client request: %s %s%s", req.Method, req.URL.Host, req.URL.Path), fields...)
+
+	if t.options.WithPayload && nil != req.Body {
+		buf, _ := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewBuffer(buf))
+		log.Debug("http client request payload",
+			zap.String("payload", string(logger.GetRedactor().Redact(buf))),
+		)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	code := TRANSPORT_ERROR
+	if nil != err {
+		log.Warn(fmt.Sprintf("end http client request: %s", elapsed),
+			append(fields, zap.Duration("elapsed", elapsed), zap.Error(err))...,
+		)
+	}
+	if nil == err {
+		code = fmt.Sprint(resp.StatusCode)
+		log.Info(fmt.Sprintf("end http client request: %s", elapsed),
+			append(fields, zap.Int("status", resp.StatusCode), zap.Duration("elapsed", elapsed))...,
+		)
+
+		if t.options.WithPayload && nil != resp.Body {
+			buf, _ := ioutil.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewBuffer(buf))
+			log.Debug("http client response payload",
+				zap.String("payload", string(logger.GetRedactor().Redact(buf))),
+				zap.Int("http status", resp.StatusCode),
+			)
+		}
+	}
+
+	if t.options.WithPrometheus {
+		prometheusclient.IncrementClientRequestCounter(upstream, route, req.Method, code)
+		prometheusclient.ObserveClientRequestDuration(upstream, route, req.Method, code, start)
+	}
+
+	return resp, err
+}
+
+func (t *transport) getLogger(ctx context.Context) *zap.Logger {
+	if logger.HasLogger(ctx) {
+		return logger.GetLogger(ctx)
+	}
+	if nil != t.options.Logger {
+		return t.options.Logger
+	}
+	return zap.NewNop()
+}
@@ -9,5 +9,5 @@ func BadRequest(err error) Interface {
 
 // IsBadRequestError check whether given error is a BadRequestError
 func IsBadRequestError(err error) bool {
-	return err != nil && GetInstance(err).GetCode() == http.StatusBadRequest
+	return hasCode(err, http.StatusBadRequest)
 }
@@ -0,0 +1,37 @@
+package httperror
+
+// statusClass is sentinel error matched by every Base error with code in the class range
+type statusClass struct {
+	min  int
+	max  int
+	name string
+}
+
+func (c *statusClass) Error() string {
+	return c.name
+}
+
+// ErrClient and ErrServer are status class sentinels, errors.Is only match httperror in the error chain, use
+// IsClientError and IsServerError to treat error chain without httperror as InternalServerError like GetInstance
+var (
+	ErrClient = &statusClass{min: 400, max: 499, name: "client error"} // 4xx status class, use with errors.Is
+	ErrServer = &statusClass{min: 500, max: 599, name: "server error"} // 5xx status class, use with errors.Is
+)
+
+// IsClientError check whether http code of the error is 4xx
+func IsClientError(err error) bool {
+	return inClass(err, ErrClient)
+}
+
+// IsServerError check whether http code of the error is 5xx, error chain without httperror is a server error
+func IsServerError(err error) bool {
+	return inClass(err, ErrServer)
+}
+
+func inClass(err error, class *statusClass) bool {
+	if nil == err {
+		return false
+	}
+	code := GetInstance(err).GetCode()
+	return code >= class.min && code <= class.max
+}
@@ -0,0 +1,43 @@
+package httperror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStatusHelpers(t *testing.T) {
+	plain := errors.New("boom")
+	wrapped := fmt.Errorf("create order: %w", BadRequest(plain))
+
+	tests := []struct {
+		name     string
+		check    func(err error) bool
+		err      error
+		expected bool
+	}{
+		{"bad request", IsBadRequestError, wrapped, true},
+		{"not bad request", IsBadRequestError, plain, false},
+		{"plain internal server error", IsInternalServerError, plain, true},
+		{"wrapped is not internal server error", IsInternalServerError, wrapped, false},
+		{"nil", IsInternalServerError, nil, false},
+		{"client error", IsClientError, wrapped, true},
+		{"plain server error", IsServerError, plain, true},
+		{"server error", IsServerError, fmt.Errorf("call: %w", BadGateway(plain)), true},
+		{"nil server error", IsServerError, nil, false},
+	}
+	for _, test := range tests {
+		if result := test.check(test.err); test.expected != result {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, result)
+		}
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	if !errors.Is(fmt.Errorf("create order: %w", NotFound(errors.New("x"))), ErrClient) {
+		t.Error("wrapped 404 should match ErrClient")
+	}
+	if errors.Is(NotFound(errors.New("x")), ErrServer) {
+		t.Error("404 should not match ErrServer")
+	}
+}
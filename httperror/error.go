@@ -1,6 +1,7 @@
 package httperror
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -19,8 +20,11 @@ type Interface interface {
 type Base struct {
 	Code       int    `json:"-"`
 	StatusCode string `json:"code"`
+	ErrorCode  string `json:"errorCode,omitempty"` // business code of registry definition
 	Detail     error  `json:"-"`
 	Message    string `json:"message"`
+
+	definition *Definition
 }
 
 // Error implement error interface, and return error Message
@@ -61,27 +65,54 @@ func (e *Base) SetMessage(message string) {
 	e.Message = message
 }
 
+// Unwrap return error detail, so errors.Is and errors.As can inspect the detail
+func (e *Base) Unwrap() error {
+	return e.Detail
+}
+
+// Is report whether the error belong to status class sentinel or registry definition,
+// ex: errors.Is(err, httperror.ErrClient); errors.Is(err, ErrInsufficientBalance)
+func (e *Base) Is(target error) bool {
+	switch t := target.(type) {
+	case *statusClass:
+		return e.Code >= t.min && e.Code <= t.max
+	case *Definition:
+		return e.definition == t
+	}
+	return false
+}
+
 // Base constructor for http error with custom message
 func New(code int, err error) Interface {
 	if nil == err {
 		return nil
 	}
 
-	if base := GetInstance(err); nil != base {
-		err = base.GetDetail()
-	}
-
-	return &Base{
+	result := &Base{
 		Code:       code,
 		StatusCode: http.StatusText(code),
 		Detail:     err,
 		Message:    err.Error(),
 	}
+
+	// keep business code and public message of registry definition
+	if base, ok := err.(*Base); ok {
+		result.Detail = base.GetDetail()
+		result.Message = base.GetDetail().Error()
+		if nil != base.definition {
+			result.ErrorCode = base.ErrorCode
+			result.Message = base.Message
+			result.definition = base.definition
+		}
+	}
+
+	return result
 }
 
-// GetInstance get Base error instance from error interface, will return wrapped error with 500 http code on non Base error
+// GetInstance get Base error instance from error chain, will return wrapped error with 500 http code on non Base error
 func GetInstance(err error) Interface {
-	if result, ok := err.(*Base); ok {
+	var result *Base
+	if errors.As(err, &result) {
 		return result
 	}
 	return &Base{
@@ -91,3 +122,9 @@ func GetInstance(err error) Interface {
 		Message:    err.Error(),
 	}
 }
+
+// hasCode check whether http code of the error is the given code, error chain without httperror is treated as
+// InternalServerError like GetInstance
+func hasCode(err error, code int) bool {
+	return nil != err && code == GetInstance(err).GetCode()
+}
@@ -7,7 +7,8 @@ func InternalServer(err error) Interface {
 	return New(http.StatusInternalServerError, err)
 }
 
-// IsInternalServerError check whether given error is a InternalServerError
+// IsInternalServerError check whether given error is a InternalServerError, error chain without httperror is treated
+// as InternalServerError
 func IsInternalServerError(err error) bool {
-	return err != nil && GetInstance(err).GetCode() == http.StatusInternalServerError
+	return hasCode(err, http.StatusInternalServerError)
 }
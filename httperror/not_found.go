@@ -9,5 +9,5 @@ func NotFound(err error) Interface {
 
 // IsNotFoundError check whether given error is a NotFoundError
 func IsNotFoundError(err error) bool {
-	return err != nil && GetInstance(err).GetCode() == http.StatusNotFound
+	return hasCode(err, http.StatusNotFound)
 }
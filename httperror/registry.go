@@ -0,0 +1,154 @@
+package httperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Definition is application error defined once per service, it can be used as errors.Is target
+type Definition struct {
+	Code     string        `json:"code"`     // stable business code, ex: PAYMENT_INSUFFICIENT_BALANCE
+	Status   int           `json:"status"`   // http status code
+	Message  string        `json:"message"`  // default public message
+	LogLevel zapcore.Level `json:"logLevel"` // level used to log the error, see GetLogLevel
+}
+
+func (d *Definition) Error() string {
+	return d.Code
+}
+
+// New create http error of the definition with default public message, err is kept as detail for logging,
+// the public message is used as detail if err is nil
+func (d *Definition) New(err error) Interface {
+	if nil == err {
+		err = errors.New(d.Message)
+	}
+
+	return &Base{
+		Code:       d.Status,
+		StatusCode: http.StatusText(d.Status),
+		ErrorCode:  d.Code,
+		Detail:     err,
+		Message:    d.Message,
+		definition: d,
+	}
+}
+
+// Newf create http error of the definition with formatted detail
+func (d *Definition) Newf(format string, args ...interface{}) Interface {
+	return d.New(fmt.Errorf(format, args...))
+}
+
+// Registry is collection of application error definitions
+type Registry struct {
+	lock        sync.RWMutex
+	definitions map[string]*Definition
+}
+
+// DefaultRegistry is registry used by Define
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry create empty error registry
+func NewRegistry() *Registry {
+	return &Registry{
+		definitions: map[string]*Definition{},
+	}
+}
+
+// Define register application error to DefaultRegistry, see Registry.Define
+func Define(code string, status int, message string, level zapcore.Level) *Definition {
+	return DefaultRegistry.Define(code, status, message, level)
+}
+
+// Define register application error, should be called on package initialization, panic on duplicate code, ex:
+//
+//	var ErrInsufficientBalance = httperror.Define("PAYMENT_INSUFFICIENT_BALANCE", http.StatusUnprocessableEntity,
+//		"insufficient balance", zapcore.WarnLevel)
+func (r *Registry) Define(code string, status int, message string, level zapcore.Level) *Definition {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.definitions[code]; ok {
+		panic(fmt.Sprintf("httperror: duplicate error code %s", code))
+	}
+
+	d := &Definition{
+		Code:     code,
+		Status:   status,
+		Message:  message,
+		LogLevel: level,
+	}
+	r.definitions[code] = d
+
+	return d
+}
+
+// Get get definition by business code
+func (r *Registry) Get(code string) (*Definition, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	d, ok := r.definitions[code]
+	return d, ok
+}
+
+// Definitions get every definition sorted by business code
+func (r *Registry) Definitions() []Definition {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	definitions := make([]Definition, 0, len(r.definitions))
+	for _, d := range r.definitions {
+		definitions = append(definitions, *d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+
+	return definitions
+}
+
+// JSON export definitions as JSON array for client teams
+func (r *Registry) JSON() ([]byte, error) {
+	return json.MarshalIndent(r.Definitions(), "", "  ")
+}
+
+// Markdown export definitions as markdown table for client teams
+func (r *Registry) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("| Code | HTTP Status | Message | Log Level |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, d := range r.Definitions() {
+		fmt.Fprintf(&sb, "| `%s` | %d %s | %s | %s |\n",
+			d.Code, d.Status, http.StatusText(d.Status), strings.Replace(d.Message, "|", "\\|", -1), d.LogLevel)
+	}
+	return sb.String()
+}
+
+// GetDefinition get registry definition the error is created from
+func GetDefinition(err error) (*Definition, bool) {
+	var base *Base
+	if errors.As(err, &base) && nil != base.definition {
+		return base.definition, true
+	}
+	return nil, false
+}
+
+// GetLogLevel get log level of the error, level of the registry definition is used if the error is created from
+// a definition, otherwise error level for 5xx error and warn level for the others
+func GetLogLevel(err error) zapcore.Level {
+	if d, ok := GetDefinition(err); ok {
+		return d.LogLevel
+	}
+	if GetInstance(err).GetCode() >= http.StatusInternalServerError {
+		return zapcore.ErrorLevel
+	}
+	return zapcore.WarnLevel
+}
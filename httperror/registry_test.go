@@ -0,0 +1,133 @@
+package httperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRegistryDefine(t *testing.T) {
+	registry := NewRegistry()
+	balance := registry.Define("PAYMENT_INSUFFICIENT_BALANCE", http.StatusUnprocessableEntity, "insufficient balance", zapcore.WarnLevel)
+	registry.Define("AUTH_EXPIRED_TOKEN", http.StatusUnauthorized, "token | expired", zapcore.InfoLevel)
+
+	d, ok := registry.Get("PAYMENT_INSUFFICIENT_BALANCE")
+	if !ok || balance != d {
+		t.Fatalf("defined error should be found by code, got %v", d)
+	}
+	if _, ok := registry.Get("UNKNOWN"); ok {
+		t.Error("undefined code should not be found")
+	}
+
+	definitions := registry.Definitions()
+	if 2 != len(definitions) || "AUTH_EXPIRED_TOKEN" != definitions[0].Code {
+		t.Errorf("definitions should be sorted by code, got %v", definitions)
+	}
+
+	by, err := registry.JSON()
+	if nil != err {
+		t.Fatal(err)
+	}
+	var exported []Definition
+	if err := json.Unmarshal(by, &exported); nil != err || 2 != len(exported) || zapcore.WarnLevel != exported[1].LogLevel {
+		t.Errorf("unexpected json export %s: %v", by, err)
+	}
+
+	markdown := registry.Markdown()
+	if !strings.Contains(markdown, "| `PAYMENT_INSUFFICIENT_BALANCE` | 422 Unprocessable Entity | insufficient balance | warn |") ||
+		!strings.Contains(markdown, `token \| expired`) {
+		t.Errorf("unexpected markdown export:\n%s", markdown)
+	}
+}
+
+func TestRegistryDefineDuplicate(t *testing.T) {
+	registry := NewRegistry()
+	registry.Define("DUPLICATE", http.StatusConflict, "duplicate", zapcore.WarnLevel)
+
+	defer func() {
+		if nil == recover() {
+			t.Error("duplicate code should panic")
+		}
+	}()
+	registry.Define("DUPLICATE", http.StatusConflict, "duplicate", zapcore.WarnLevel)
+}
+
+func TestDefinitionNew(t *testing.T) {
+	balance := NewRegistry().Define("PAYMENT_INSUFFICIENT_BALANCE", http.StatusUnprocessableEntity, "insufficient balance", zapcore.InfoLevel)
+	detail := errors.New("balance 10 < amount 20")
+
+	err := fmt.Errorf("pay: %w", balance.New(detail))
+	be := GetInstance(err)
+	if http.StatusUnprocessableEntity != be.GetCode() || "insufficient balance" != be.GetMessage() || detail != be.GetDetail() {
+		t.Errorf("unexpected error: %+v", be)
+	}
+	if "PAYMENT_INSUFFICIENT_BALANCE" != be.(*Base).ErrorCode {
+		t.Errorf("business code should be set, got %s", be.(*Base).ErrorCode)
+	}
+	if !errors.Is(err, balance) || !errors.Is(err, detail) || !errors.Is(err, ErrClient) {
+		t.Error("error should match its definition, detail and status class")
+	}
+	if zapcore.InfoLevel != GetLogLevel(err) {
+		t.Errorf("definition log level should be used, got %s", GetLogLevel(err))
+	}
+
+	if "insufficient balance" != balance.New(nil).GetDetail().Error() {
+		t.Error("public message should be used as detail when err is nil")
+	}
+	if "balance 10" != balance.Newf("balance %d", 10).GetDetail().Error() {
+		t.Error("formatted detail should be used")
+	}
+}
+
+func TestNewKeepDefinition(t *testing.T) {
+	balance := NewRegistry().Define("PAYMENT_INSUFFICIENT_BALANCE", http.StatusUnprocessableEntity, "insufficient balance", zapcore.InfoLevel)
+
+	err := New(http.StatusConflict, balance.New(errors.New("detail")))
+	base := err.(*Base)
+	if http.StatusConflict != base.Code || "PAYMENT_INSUFFICIENT_BALANCE" != base.ErrorCode || "insufficient balance" != base.Message {
+		t.Errorf("business code and message should be kept, got %+v", base)
+	}
+	if !errors.Is(err, balance) {
+		t.Error("error should still match its definition")
+	}
+
+	plain := New(http.StatusConflict, BadRequest(errors.New("detail"))).(*Base)
+	if "" != plain.ErrorCode || "detail" != plain.Message {
+		t.Errorf("unexpected error: %+v", plain)
+	}
+}
+
+func TestGetLogLevel(t *testing.T) {
+	if zapcore.ErrorLevel != GetLogLevel(errors.New("boom")) {
+		t.Error("plain error should be logged at error level")
+	}
+	if zapcore.WarnLevel != GetLogLevel(NotFound(errors.New("missing"))) {
+		t.Error("client error should be logged at warn level")
+	}
+	if zapcore.ErrorLevel != GetLogLevel(BadGateway(errors.New("upstream"))) {
+		t.Error("server error should be logged at error level")
+	}
+}
+
+func TestDefine(t *testing.T) {
+	d := Define("HTTPERROR_TEST_DEFINE", http.StatusTeapot, "teapot", zapcore.DebugLevel)
+	if found, ok := DefaultRegistry.Get("HTTPERROR_TEST_DEFINE"); !ok || d != found {
+		t.Error("Define should register to DefaultRegistry")
+	}
+}
+
+func TestGetDefinition(t *testing.T) {
+	balance := NewRegistry().Define("PAYMENT_INSUFFICIENT_BALANCE", http.StatusUnprocessableEntity, "insufficient balance", zapcore.InfoLevel)
+
+	if d, ok := GetDefinition(New(http.StatusConflict, balance.New(nil))); !ok || balance != d {
+		t.Error("definition should be found through wrapping error")
+	}
+	if _, ok := GetDefinition(NotFound(errors.New("missing"))); ok {
+		t.Error("plain http error should not have definition")
+	}
+}
@@ -9,5 +9,5 @@ func RequestTimeout(err error) Interface {
 
 // IsRequestTimeoutError check whether given error is a RequestTimeoutError
 func IsRequestTimeoutError(err error) bool {
-	return err != nil && GetInstance(err).GetCode() == http.StatusRequestTimeout
+	return hasCode(err, http.StatusRequestTimeout)
 }
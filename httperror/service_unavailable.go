@@ -9,5 +9,5 @@ func ServiceUnavailable(err error) Interface {
 
 // IsServiceUnavailableError check whether given error is a ServiceUnavailableError
 func IsServiceUnavailableError(err error) bool {
-	return err != nil && GetInstance(err).GetCode() == http.StatusServiceUnavailable
+	return hasCode(err, http.StatusServiceUnavailable)
 }
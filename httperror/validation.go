@@ -0,0 +1,20 @@
+package httperror
+
+import "strings"
+
+// FieldError is validation error of a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is list of invalid request fields, use it as BadRequest or UnprocessableEntity detail
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		messages = append(messages, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(messages, ", ")
+}
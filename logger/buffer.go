@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var requestBufferSize int64
+
+type requestBufferKeyType struct{}
+
+var requestBufferKey requestBufferKeyType
+
+// SetRequestBufferSize enable tail based request log buffering, debug and info entries are kept in a ring buffer of given size per request and only written when the request fails (5xx http status, grpc error or any error
+// level log), buffering is disabled when size is 0
+func SetRequestBufferSize(size int) {
+	atomic.StoreInt64(&requestBufferSize, int64(size))
+}
+
+// FlushRequestLog write buffered request log entries and write the next entries directly, use it to mark the request
+// as failed on the cases that are not detected automatically
+func FlushRequestLog(ctx context.Context) {
+	if buffer, ok := ctx.Value(requestBufferKey).(*requestBuffer); ok {
+		buffer.flush()
+	}
+}
+
+type bufferedEntry struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+type requestBuffer struct {
+	lock    sync.Mutex
+	entries []bufferedEntry
+	next    int
+	full    bool
+	failed  bool
+}
+
+func newRequestBuffer(ctx context.Context, logger *zap.Logger) (context.Context, *zap.Logger) {
+	size := atomic.LoadInt64(&requestBufferSize)
+	if size <= 0 {
+		return ctx, logger
+	}
+
+	buffer := &requestBuffer{
+		entries: make([]bufferedEntry, size),
+	}
+	logger = logger.WithOptions(
+		zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return &bufferCore{Core: c, buffer: buffer}
+		}),
+	)
+
+	return context.WithValue(ctx, requestBufferKey, buffer), logger
+}
+
+func (b *requestBuffer) add(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) error {
+	b.lock.Lock()
+	if b.failed {
+		b.lock.Unlock()
+		return core.Write(ent, fields)
+	}
+	defer b.lock.Unlock()
+
+	b.entries[b.next] = bufferedEntry{
+		core:   core,
+		entry:  ent,
+		fields: snapshot(fields),
+	}
+	b.next = (b.next + 1) % len(b.entries)
+	if 0 == b.next {
+		b.full = true
+	}
+	return nil
+}
+
+func (b *requestBuffer) flush() {
+	b.lock.Lock()
+	if b.failed {
+		b.lock.Unlock()
+		return
+	}
+	b.failed = true
+
+	var entries []bufferedEntry
+	if b.full {
+		entries = append(entries, b.entries[b.next:]...)
+	}
+	entries = append(entries, b.entries[:b.next]...)
+	b.entries = nil
+	b.lock.Unlock()
+
+	for _, e := range entries {
+		_ = e.core.Write(e.entry, e.fields)
+	}
+}
+
+// snapshot encode fields when the entry is buffered, so the buffer doesn't retain field values that may be mutated by
+// the caller before the entry is flushed, the fields are ordered by key
+func snapshot(fields []zapcore.Field) []zapcore.Field {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		// map encoder keep reflected value as is, copy it through json
+		if zapcore.ReflectType == field.Type {
+			field = copyReflected(field)
+		}
+		field.AddTo(enc)
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]zapcore.Field, 0, len(keys)+1)
+	for _, key := range keys {
+		result = append(result, zap.Any(key, enc.Fields[key]))
+	}
+	return append(result, zap.Bool("buffered", true))
+}
+
+func copyReflected(field zapcore.Field) zapcore.Field {
+	by, err := json.Marshal(field.Interface)
+	if nil != err {
+		return zap.String(field.Key, fmt.Sprintf("%+v", field.Interface))
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(by, &value); nil != err {
+		return zap.String(field.Key, string(by))
+	}
+	return zap.Any(field.Key, value)
+}
+
+// bufferCore keep debug and info entries in request buffer until the request fails, wrapped core Write is expected
+// to not check the level
+type bufferCore struct {
+	zapcore.Core
+	buffer *requestBuffer
+}
+
+func (c *bufferCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *bufferCore) With(fields []zapcore.Field) zapcore.Core {
+	return &bufferCore{Core: c.Core.With(fields), buffer: c.buffer}
+}
+
+func (c *bufferCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= zapcore.ErrorLevel {
+		c.buffer.flush()
+	}
+	if ent.Level >= zapcore.WarnLevel {
+		return c.Core.Check(ent, ce)
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *bufferCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.buffer.add(c.Core, ent, fields)
+}
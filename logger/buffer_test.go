@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+)
+
+func newBufferedRequest(t *testing.T, size int) (context.Context, *observer.ObservedLogs, func()) {
+	SetRequestBufferSize(size)
+	core, logs := observer.New(zapcore.InfoLevel)
+	return NewRequest(context.Background(), zap.New(core)), logs, func() {
+		SetRequestBufferSize(0)
+	}
+}
+
+func messages(logs *observer.ObservedLogs) []string {
+	var result []string
+	for _, entry := range logs.All() {
+		result = append(result, entry.Message)
+	}
+	return result
+}
+
+func assertMessages(t *testing.T, logs *observer.ObservedLogs, expected ...string) {
+	t.Helper()
+
+	result := messages(logs)
+	if len(expected) != len(result) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i := range expected {
+		if expected[i] != result[i] {
+			t.Fatalf("expected %v, got %v", expected, result)
+		}
+	}
+}
+
+func TestRequestBufferDropOnSuccess(t *testing.T) {
+	ctx, logs, reset := newBufferedRequest(t, 10)
+	defer reset()
+
+	log := GetLogger(ctx)
+	log.Debug("debug")
+	log.Info("info")
+	log.Warn("warn")
+
+	assertMessages(t, logs, "warn")
+}
+
+func TestRequestBufferFlushOnError(t *testing.T) {
+	ctx, logs, reset := newBufferedRequest(t, 10)
+	defer reset()
+
+	log := GetLogger(ctx)
+	log.Debug("debug")
+	log.Info("info")
+	log.Error("error")
+	log.Debug("after error")
+
+	assertMessages(t, logs, "debug", "info", "error", "after error")
+	if true != logs.All()[0].ContextMap()["buffered"] {
+		t.Error("flushed entry should be marked as buffered")
+	}
+	if _, ok := logs.All()[3].ContextMap()["buffered"]; ok {
+		t.Error("entry after flush should be written directly")
+	}
+}
+
+func TestRequestBufferRing(t *testing.T) {
+	ctx, logs, reset := newBufferedRequest(t, 2)
+	defer reset()
+
+	log := GetLogger(ctx)
+	log.Info("first")
+	log.Info("second")
+	log.Info("third")
+	FlushRequestLog(ctx)
+
+	assertMessages(t, logs, "second", "third")
+}
+
+func TestRequestBufferSnapshot(t *testing.T) {
+	ctx, logs, reset := newBufferedRequest(t, 10)
+	defer reset()
+
+	payload := map[string]interface{}{"status": "pending"}
+	GetLogger(ctx).Info("info", zap.Any("payload", payload))
+	payload["status"] = "failed"
+	FlushRequestLog(ctx)
+
+	result := logs.All()[0].ContextMap()["payload"].(map[string]interface{})
+	if "pending" != result["status"] {
+		t.Errorf("buffered field should not be changed after written, got %v", result)
+	}
+}
+
+func TestRequestBufferHttp(t *testing.T) {
+	SetRequestBufferSize(10)
+	defer SetRequestBufferSize(0)
+
+	tests := []struct {
+		status   int
+		expected int
+	}{
+		{http.StatusOK, 0},
+		{http.StatusBadRequest, 0},
+		{http.StatusInternalServerError, 1},
+	}
+	for _, test := range tests {
+		core, logs := observer.New(zapcore.InfoLevel)
+		handler := HttpMiddleware(zap.New(core))(func(w http.ResponseWriter, r *http.Request) {
+			GetLogger(r.Context()).Debug("debug")
+			w.WriteHeader(test.status)
+		})
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if test.expected != logs.Len() {
+			t.Errorf("status %d: expected %d entries, got %v", test.status, test.expected, messages(logs))
+		}
+	}
+}
+
+func TestRequestBufferGrpc(t *testing.T) {
+	SetRequestBufferSize(10)
+	defer SetRequestBufferSize(0)
+
+	for _, failed := range []bool{false, true} {
+		core, logs := observer.New(zapcore.InfoLevel)
+		interceptor := GrpcUnaryServerInterceptor(zap.New(core))
+		_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc.A/B"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			GetLogger(ctx).Debug("debug")
+			if failed {
+				return nil, errors.New("boom")
+			}
+			return nil, nil
+		})
+
+		if expected := map[bool]int{false: 0, true: 1}[failed]; expected != logs.Len() {
+			t.Errorf("failed %v: expected %d entries, got %v", failed, expected, messages(logs))
+		}
+	}
+}
+
+func TestRequestBufferDebugToken(t *testing.T) {
+	SetRequestBufferSize(10)
+	defer SetRequestBufferSize(0)
+	secret := []byte("secret")
+	SetDebugToken(secret, DEBUG_TOKEN_HEADER)
+	defer SetDebugToken(nil, DEBUG_TOKEN_HEADER)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := HttpMiddleware(zap.New(core))(func(w http.ResponseWriter, r *http.Request) {
+		GetLogger(r.Context()).Debug("debug")
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(DEBUG_TOKEN_HEADER, NewDebugToken(secret, time.Now().Add(time.Minute)))
+	handler(httptest.NewRecorder(), r)
+
+	assertMessages(t, logs, "debug")
+}
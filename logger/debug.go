@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	DEBUG_TOKEN_HEADER = "X-Debug-Token"
+)
+
+var debugTokenLock sync.RWMutex
+var debugTokenSecret []byte
+var debugTokenHeader = DEBUG_TOKEN_HEADER
+
+// SetDebugToken set secret and header (or grpc metadata) key used to escalate a single request log level to debug,
+// escalation is disabled when secret is empty
+func SetDebugToken(secret []byte, header string) {
+	debugTokenLock.Lock()
+	defer debugTokenLock.Unlock()
+	debugTokenSecret = secret
+	debugTokenHeader = header
+}
+
+func getDebugToken() ([]byte, string) {
+	debugTokenLock.RLock()
+	defer debugTokenLock.RUnlock()
+	return debugTokenSecret, debugTokenHeader
+}
+
+// NewDebugToken create signed debug token valid until expireAt, format: `<expire unix>.<hex HMAC-SHA256 of expire unix>`
+func NewDebugToken(secret []byte, expireAt time.Time) string {
+	expire := strconv.FormatInt(expireAt.Unix(), 10)
+	return expire + "." + signDebugToken(secret, expire)
+}
+
+// IsValidDebugToken check debug token signature and expiry using secret configured by SetDebugToken
+func IsValidDebugToken(token string) bool {
+	secret, _ := getDebugToken()
+	if 0 == len(secret) || "" == token {
+		return false
+	}
+
+	parts := strings.SplitN(token, ".", 2)
+	if 2 != len(parts) {
+		return false
+	}
+
+	expire, err := strconv.ParseInt(parts[0], 10, 64)
+	if nil != err || time.Now().Unix() > expire {
+		return false
+	}
+
+	return hmac.Equal([]byte(parts[1]), []byte(signDebugToken(secret, parts[0])))
+}
+
+// GetDebugTokenFromHeader get debug token from configured header
+func GetDebugTokenFromHeader(header http.Header) string {
+	_, key := getDebugToken()
+	if "" == key {
+		return ""
+	}
+	return header.Get(key)
+}
+
+// GetDebugTokenFromMetadata get debug token from configured grpc incoming metadata key
+func GetDebugTokenFromMetadata(ctx context.Context) string {
+	_, key := getDebugToken()
+	md, ok := metadata.FromIncomingContext(ctx)
+	if "" == key || !ok {
+		return ""
+	}
+	if values := md.Get(key); 0 < len(values) {
+		return values[0]
+	}
+	return ""
+}
+
+func signDebugToken(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// debugCore enable every level regardless of wrapped core level, wrapped core Write is expected to not check the level
+type debugCore struct {
+	zapcore.Core
+}
+
+func escalateDebug(logger *zap.Logger) *zap.Logger {
+	return logger.WithOptions(
+		zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return &debugCore{Core: c}
+		}),
+	)
+}
+
+func (c *debugCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *debugCore) With(fields []zapcore.Field) zapcore.Core {
+	return &debugCore{Core: c.Core.With(fields)}
+}
+
+func (c *debugCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+type requestFieldsKeyType struct{}
+
+var requestFieldsKey requestFieldsKeyType
+
+type requestFields struct {
+	lock   sync.RWMutex
+	fields []zap.Field
+}
+
+// AddFields add fields to request scoped logger, the fields will be included on every logger got from GetLogger
+// after this call and on end of request log, field with the same key will be replaced
+func AddFields(ctx context.Context, fields ...zap.Field) {
+	rf, ok := ctx.Value(requestFieldsKey).(*requestFields)
+	if !ok {
+		return
+	}
+
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	for _, field := range fields {
+		replaced := false
+		for i := range rf.fields {
+			if rf.fields[i].Key == field.Key {
+				rf.fields[i] = field
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			rf.fields = append(rf.fields, field)
+		}
+	}
+}
+
+// GetFields get fields added by AddFields
+func GetFields(ctx context.Context) []zap.Field {
+	rf, ok := ctx.Value(requestFieldsKey).(*requestFields)
+	if !ok {
+		return nil
+	}
+
+	rf.lock.RLock()
+	defer rf.lock.RUnlock()
+
+	return append([]zap.Field(nil), rf.fields...)
+}
+
+func newRequestFields(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestFieldsKey, &requestFields{})
+}
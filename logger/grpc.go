@@ -23,28 +23,50 @@ const (
 func GrpcMiddleware(logger *zap.Logger) endpoint.Middleware {
 	return func(f endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, in interface{}) (out interface{}, err error) {
-			newCtx := NewRequest(ctx, logger)
-			return f(newCtx, in)
+			newCtx := newIncomingRequest(ctx, logger)
+			_ = grpc.SetHeader(newCtx, ResponseRequestIDMetadata(RequestID(newCtx)))
+			out, err = f(newCtx, in)
+			if nil != err {
+				FlushRequestLog(newCtx)
+			}
+			return out, err
 		}
 	}
 }
 
 func GrpcUnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		newCtx := NewRequest(ctx, logger)
-		return handler(newCtx, req)
+		newCtx := newIncomingRequest(ctx, logger)
+		_ = grpc.SetHeader(newCtx, ResponseRequestIDMetadata(RequestID(newCtx)))
+		resp, err = handler(newCtx, req)
+		if nil != err {
+			FlushRequestLog(newCtx)
+		}
+		return resp, err
 	}
 }
 
 func GrpcStreamServerInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		newCtx := NewRequest(ss.Context(), logger)
+		newCtx := newIncomingRequest(ss.Context(), logger)
+		_ = ss.SetHeader(ResponseRequestIDMetadata(RequestID(newCtx)))
 		wrappedStream := grpc_middleware.WrapServerStream(ss)
 		wrappedStream.WrappedContext = newCtx
-		return handler(srv, wrappedStream)
+		err := handler(srv, wrappedStream)
+		if nil != err {
+			FlushRequestLog(newCtx)
+		}
+		return err
 	}
 }
 
+func newIncomingRequest(ctx context.Context, logger *zap.Logger) context.Context {
+	return newRequest(ctx, logger,
+		GetRequestIDFromMetadata(ctx),
+		IsValidDebugToken(GetDebugTokenFromMetadata(ctx)),
+	)
+}
+
 func GrpcEndpointUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		log := GetLogger(ctx)
@@ -60,6 +82,7 @@ func GrpcEndpointUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		elapsed := time.Since(start)
 		code := status.Code(err).String()
 
+		log = GetLogger(ctx)
 		log.Info(fmt.Sprintf("end grpc request: %s", elapsed),
 			zap.Duration("elapsed", elapsed),
 			zap.String("service", service),
@@ -86,6 +109,7 @@ func GrpcEndpointStreamServerInterceptor() grpc.StreamServerInterceptor {
 		elapsed := time.Since(start)
 		code := status.Code(err).String()
 
+		log = GetLogger(ss.Context())
 		log.Info(fmt.Sprintf("end grpc request: %s", elapsed),
 			zap.Duration("elapsed", elapsed),
 			zap.String("service", service),
@@ -103,24 +127,28 @@ func GrpcEndpointStreamServerInterceptor() grpc.StreamServerInterceptor {
 // GrpcEndpointStreamServerInterceptor instead
 func GrpcEndpointMiddleware() endpoint.Middleware {
 	return func(f endpoint.Endpoint) endpoint.Endpoint {
-		var start time.Time
-		return DoGRPC(
-			f,
-			func(ctx context.Context, log *zap.Logger, in interface{}) error {
-				pcs := make([]uintptr, MAX_CALLER)
-				n := runtime.Callers(0, pcs)
-
-				funcName := runtime.FuncForPC(pcs[n-CALLER_OFFSET]).Name()
-				log.Info(fmt.Sprintf("begin grpc request: %s", funcName))
-				start = time.Now()
-				return nil
-			},
-			func(ctx context.Context, log *zap.Logger, out interface{}) error {
-				elapsed := time.Since(start)
-				log.Info(fmt.Sprintf("end grpc request: %s", elapsed))
-				return nil
-			},
-		)
+		return func(ctx context.Context, in interface{}) (out interface{}, err error) {
+			var start time.Time
+			return DoGRPC(
+				f,
+				func(ctx context.Context, log *zap.Logger, in interface{}) error {
+					pcs := make([]uintptr, MAX_CALLER)
+					n := runtime.Callers(0, pcs)
+
+					funcName := runtime.FuncForPC(pcs[n-CALLER_OFFSET]).Name()
+					log.Info(fmt.Sprintf("begin grpc request: %s", funcName))
+					start = time.Now()
+					return nil
+				},
+				func(ctx context.Context, log *zap.Logger, out interface{}) error {
+					elapsed := time.Since(start)
+					log.Info(fmt.Sprintf("end grpc request: %s", elapsed),
+						zap.Duration("elapsed", elapsed),
+					)
+					return nil
+				},
+			)(ctx, in)
+		}
 	}
 }
 
@@ -131,7 +159,7 @@ func GrpcRequestMiddleware() endpoint.Middleware {
 			func(ctx context.Context, log *zap.Logger, in interface{}) error {
 				body, _ := json.Marshal(in)
 				log.Debug("grpc request payload",
-					zap.String("payload", string(body)),
+					zap.String("payload", redactPayload(body)),
 				)
 				return nil
 			},
@@ -148,7 +176,7 @@ func GrpcResponseMiddleware() endpoint.Middleware {
 			func(ctx context.Context, log *zap.Logger, out interface{}) error {
 				resp, _ := json.Marshal(out)
 				log.Debug("grpc response payload",
-					zap.String("payload", string(resp)),
+					zap.String("payload", redactPayload(resp)),
 				)
 				return nil
 			},
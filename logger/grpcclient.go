@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcUnaryClientInterceptor ensure outgoing call has request scoped logger and propagate request id to upstream,
+// logger is used when the call context doesn't have request scoped logger
+func GrpcUnaryClientInterceptor(logger *zap.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(newOutgoingContext(ctx, logger), method, req, reply, cc, opts...)
+	}
+}
+
+// GrpcStreamClientInterceptor is the stream version of GrpcUnaryClientInterceptor
+func GrpcStreamClientInterceptor(logger *zap.Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(newOutgoingContext(ctx, logger), desc, cc, method, opts...)
+	}
+}
+
+func GrpcEndpointUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, fullMethod string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		log := GetLogger(ctx)
+		service := path.Dir(fullMethod)[1:]
+		method := path.Base(fullMethod)
+
+		log.Info(fmt.Sprintf("begin grpc client request: %s/%s", service, method),
+			zap.String("target", cc.Target()),
+			zap.String("service", service),
+			zap.String("method", method),
+		)
+		start := time.Now()
+		err := invoker(ctx, fullMethod, req, reply, cc, opts...)
+		elapsed := time.Since(start)
+		code := status.Code(err).String()
+
+		log.Info(fmt.Sprintf("end grpc client request: %s", elapsed),
+			zap.Duration("elapsed", elapsed),
+			zap.String("target", cc.Target()),
+			zap.String("service", service),
+			zap.String("method", method),
+			zap.String("code", code),
+		)
+
+		return err
+	}
+}
+
+// GrpcEndpointStreamClientInterceptor log begin and end of client stream, the end is logged when the stream is
+// finished with io.EOF or error on RecvMsg
+func GrpcEndpointStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, fullMethod string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		log := GetLogger(ctx)
+		service := path.Dir(fullMethod)[1:]
+		method := path.Base(fullMethod)
+
+		log.Info(fmt.Sprintf("begin grpc client stream: %s/%s", service, method),
+			zap.String("target", cc.Target()),
+			zap.String("service", service),
+			zap.String("method", method),
+		)
+		stream := &endpointClientStream{
+			log:           log,
+			start:         time.Now(),
+			serverStreams: desc.ServerStreams,
+			fields: []zap.Field{
+				zap.String("target", cc.Target()),
+				zap.String("service", service),
+				zap.String("method", method),
+			},
+		}
+
+		var err error
+		stream.ClientStream, err = streamer(ctx, desc, cc, fullMethod, opts...)
+		if nil != err {
+			stream.end(err)
+			return nil, err
+		}
+
+		return stream, nil
+	}
+}
+
+type endpointClientStream struct {
+	grpc.ClientStream
+	log           *zap.Logger
+	start         time.Time
+	serverStreams bool
+	fields        []zap.Field
+	once          sync.Once
+}
+
+func (s *endpointClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if nil != err || !s.serverStreams {
+		s.end(err)
+	}
+	return err
+}
+
+func (s *endpointClientStream) end(err error) {
+	if io.EOF == err {
+		err = nil
+	}
+
+	s.once.Do(func() {
+		elapsed := time.Since(s.start)
+		fields := append([]zap.Field{zap.Duration("elapsed", elapsed)}, s.fields...)
+		fields = append(fields, zap.String("code", status.Code(err).String()))
+		s.log.Info(fmt.Sprintf("end grpc client stream: %s", elapsed), fields...)
+	})
+}
+
+func GrpcPayloadUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		log := GetLogger(ctx)
+
+		body, _ := json.Marshal(req)
+		log.Debug("grpc client request payload",
+			zap.String("payload", redactPayload(body)),
+		)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if nil != err {
+			return err
+		}
+
+		resp, _ := json.Marshal(reply)
+		log.Debug("grpc client response payload",
+			zap.String("payload", redactPayload(resp)),
+		)
+
+		return nil
+	}
+}
+
+func newOutgoingContext(ctx context.Context, logger *zap.Logger) context.Context {
+	if !HasLogger(ctx) {
+		if nil == logger {
+			logger = zap.NewNop()
+		}
+		ctx = NewRequest(ctx, logger)
+	}
+
+	header := strings.ToLower(RequestIDHeader())
+	requestId := RequestID(ctx)
+	if "" == header || "" == requestId {
+		return ctx
+	}
+
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && 0 < len(md.Get(header)) {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, header, requestId)
+}
@@ -1,9 +1,9 @@
 package logger
 
 import (
-	"encoding/json"
 	"net/http"
 
+	router "github.com/payfazz/fz-router"
 	"go.uber.org/zap"
 )
 
@@ -15,7 +15,16 @@ func GetIP(r *http.Request) string {
 	return r.RemoteAddr
 }
 
+// GetRoute get route pattern injected by `fz-router`, will return empty string if the pattern is not injected
+func GetRoute(r *http.Request) (route string) {
+	defer func() {
+		if nil != recover() {
+			route = ""
+		}
+	}()
+	return router.GetPattern(r)
+}
+
 func GetZapPayloadField(payload interface{}) zap.Field {
-	by, _ := json.Marshal(payload)
-	return zap.String("payload", string(by))
+	return zap.String("payload", string(GetRedactor().RedactValue(payload)))
 }
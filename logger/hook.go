@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"github.com/payfazz/fz-sentry/sentrycore"
 	"github.com/payfazz/fz-sentry/slackcore"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -15,6 +16,13 @@ func SlackHook(slackHookUrl string, outLevel zapcore.Level) zap.Option {
 	)
 }
 
+// SlackHookWithOptions create slack hook with routing, deduplication and delivery options, see slackcore.Options
+func SlackHookWithOptions(options slackcore.Options) zap.Option {
+	return zap.WrapCore(
+		slackcore.NewWrapperWithOptions(options),
+	)
+}
+
 func DebugSlackHook(slackHookUrl string) zap.Option {
 	return SlackHook(slackHookUrl, zapcore.DebugLevel)
 }
@@ -30,3 +38,12 @@ func WarnSlackHook(slackHookUrl string) zap.Option {
 func ErrorSlackHook(slackHookUrl string) zap.Option {
 	return SlackHook(slackHookUrl, zapcore.ErrorLevel)
 }
+
+// SentryHook create sentry hook, error is returned if dsn is invalid
+func SentryHook(dsn string, outLevel zapcore.Level, options sentrycore.Options) (zap.Option, error) {
+	wrapper, err := sentrycore.NewWrapper(dsn, outLevel, options)
+	if nil != err {
+		return nil, err
+	}
+	return zap.WrapCore(wrapper), nil
+}
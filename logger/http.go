@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/payfazz/fz-sentry/httperror"
+	"github.com/payfazz/fz-sentry/loghttp"
 	"go.uber.org/zap"
 )
 
@@ -15,28 +17,75 @@ func HttpMiddleware(logger *zap.Logger) func(next http.HandlerFunc) http.Handler
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
-			ctx = NewRequest(ctx, logger)
-			next(w, r.WithContext(ctx))
+			ctx = newRequest(ctx, logger,
+				GetRequestIDFromHeader(r.Header),
+				IsValidDebugToken(GetDebugTokenFromHeader(r.Header)),
+			)
+			SetResponseRequestID(w, RequestID(ctx))
+
+			options := loghttp.GetErrorOptions(r)
+			options.RequestID = RequestID(ctx)
+			ctx = loghttp.WithErrorOptions(ctx, options)
+
+			wr := loghttp.WrapWriter(w)
+			next(wr, r.WithContext(ctx))
+
+			if nil != wr.Err {
+				logHttpError(ctx, wr.Err)
+			}
+
+			if wr.StatusCode >= http.StatusInternalServerError {
+				FlushRequestLog(ctx)
+			}
 		}
 	}
 }
 
+// logHttpError log error response created from a registry definition at the definition level, other errors are
+// left to the handler to log
+func logHttpError(ctx context.Context, err error) {
+	d, ok := httperror.GetDefinition(err)
+	if !ok {
+		return
+	}
+
+	be := httperror.GetInstance(err)
+	ce := GetLogger(ctx).Check(d.LogLevel, fmt.Sprintf("http error response: %s", be.CompleteError()))
+	if nil == ce {
+		return
+	}
+	ce.Write(zap.Int("status", be.GetCode()), zap.String("errorCode", d.Code))
+}
+
 func HttpEndpointMiddleware() func(next http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
-		var start time.Time
-		return DoHTTP(
-			next,
-			func(ctx context.Context, log *zap.Logger, r *http.Request) error {
-				log.Info(fmt.Sprintf("begin http request: %-15s | %-10s | %-15s | %-40s", GetIP(r), r.Method, r.Host, r.RequestURI))
-				start = time.Now()
-				return nil
-			},
-			func(ctx context.Context, log *zap.Logger, out []byte, code int) error {
-				elapsed := time.Since(start)
-				log.Info(fmt.Sprintf("end http request: %s", elapsed))
-				return nil
-			},
-		)
+		return func(w http.ResponseWriter, r *http.Request) {
+			log := GetLogger(r.Context())
+			ip := GetIP(r)
+
+			log.Info(fmt.Sprintf("begin http request: %-15s | %-10s | %-15s | %-40s", ip, r.Method, r.Host, r.RequestURI))
+			start := time.Now()
+
+			wr := loghttp.WrapWriter(w)
+			next(wr, r)
+
+			elapsed := time.Since(start)
+			if 0 == wr.StatusCode {
+				wr.StatusCode = http.StatusOK
+			}
+
+			log = GetLogger(r.Context())
+			log.Info(fmt.Sprintf("end http request: %s", elapsed),
+				zap.Int("status", wr.StatusCode),
+				zap.Int("bytes", wr.Size),
+				zap.Duration("elapsed", elapsed),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("route", GetRoute(r)),
+				zap.String("ip", ip),
+				zap.String("userAgent", r.UserAgent()),
+			)
+		}
 	}
 }
 
@@ -49,7 +98,7 @@ func HttpRequestMiddleware() func(next http.HandlerFunc) http.HandlerFunc {
 				body := ioutil.NopCloser(bytes.NewBuffer(buf))
 				r.Body = body
 				log.Debug("http request payload",
-					zap.String("payload", string(buf)),
+					zap.String("payload", redactPayload(buf)),
 				)
 				return nil
 			},
@@ -65,7 +114,7 @@ func HttpResponseMiddleware() func(next http.HandlerFunc) http.HandlerFunc {
 			nil,
 			func(ctx context.Context, log *zap.Logger, out []byte, code int) error {
 				log.Debug("http response payload",
-					zap.String("payload", string(out)),
+					zap.String("payload", redactPayload(out)),
 					zap.Int("http status", code),
 				)
 				return nil
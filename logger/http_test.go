@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/payfazz/fz-sentry/httperror"
+	"github.com/payfazz/fz-sentry/loghttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func serveHttpError(handler http.HandlerFunc) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	w := httptest.NewRecorder()
+	HttpMiddleware(zap.New(core))(handler)(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	return logs
+}
+
+func errorResponseLogs(logs *observer.ObservedLogs) []observer.LoggedEntry {
+	var result []observer.LoggedEntry
+	for _, entry := range logs.All() {
+		if strings.HasPrefix(entry.Message, "http error response") {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func TestHttpErrorDefinitionLogged(t *testing.T) {
+	balance := httperror.NewRegistry().Define("PAYMENT_INSUFFICIENT_BALANCE", http.StatusUnprocessableEntity, "insufficient balance", zapcore.InfoLevel)
+
+	writers := map[string]func(w http.ResponseWriter, r *http.Request, err error){
+		"Error": func(w http.ResponseWriter, r *http.Request, err error) {
+			loghttp.Error(w, err)
+		},
+		"ErrorWithRequest": loghttp.ErrorWithRequest,
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			logs := serveHttpError(func(w http.ResponseWriter, r *http.Request) {
+				write(w, r, balance.New(errors.New("balance 10")))
+			})
+
+			entries := errorResponseLogs(logs)
+			if 1 != len(entries) {
+				t.Fatalf("definition error should be logged once, got %d", len(entries))
+			}
+			if zapcore.InfoLevel != entries[0].Level || "PAYMENT_INSUFFICIENT_BALANCE" != entries[0].ContextMap()["errorCode"] {
+				t.Errorf("definition level and code should be used, got %s %v", entries[0].Level, entries[0].ContextMap())
+			}
+
+			logs = serveHttpError(func(w http.ResponseWriter, r *http.Request) {
+				write(w, r, httperror.NotFound(errors.New("missing")))
+			})
+			if entries := errorResponseLogs(logs); 0 != len(entries) {
+				t.Errorf("error without definition should not be logged, got %+v", entries)
+			}
+		})
+	}
+}
+
+func TestHttpErrorPanicLoggedOnce(t *testing.T) {
+	logs := serveHttpError(HttpRecoveryMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	var errorLogs int
+	for _, entry := range logs.All() {
+		if zapcore.ErrorLevel == entry.Level {
+			errorLogs++
+		}
+	}
+	if 1 != errorLogs {
+		t.Errorf("panic should be logged once, got %d", errorLogs)
+	}
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/payfazz/fz-sentry/httperror"
+	"github.com/payfazz/fz-sentry/loghttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	LOG_LEVEL_PATH = "/debug/loglevel"
+)
+
+type levelPayload struct {
+	Level    *zapcore.Level `json:"level"`
+	TTL      string         `json:"ttl,omitempty"`
+	RevertAt *time.Time     `json:"revertAt,omitempty"`
+}
+
+type levelHandler struct {
+	level zap.AtomicLevel
+
+	lock      sync.Mutex
+	baseLevel zapcore.Level
+	timer     *time.Timer
+	revertAt  *time.Time
+}
+
+// LevelHandler create http handler to get (GET) and change (PUT) log level at runtime,
+// PUT payload example: {"level": "debug", "ttl": "15m"}, the level will be reverted after ttl if ttl is given
+func LevelHandler(level zap.AtomicLevel) http.Handler {
+	return &levelHandler{
+		level:     level,
+		baseLevel: level.Level(),
+	}
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.write(w)
+	case http.MethodPut:
+		var payload levelPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); nil != err {
+			loghttp.ErrorWithRequest(w, r, httperror.BadRequest(err))
+			return
+		}
+		if nil == payload.Level {
+			loghttp.ErrorWithRequest(w, r, httperror.BadRequest(errors.New("level is required")))
+			return
+		}
+
+		var ttl time.Duration
+		if "" != payload.TTL {
+			var err error
+			if ttl, err = time.ParseDuration(payload.TTL); nil != err || ttl <= 0 {
+				loghttp.ErrorWithRequest(w, r, httperror.BadRequest(fmt.Errorf("invalid ttl: %s", payload.TTL)))
+				return
+			}
+		}
+
+		h.setLevel(*payload.Level, ttl)
+		h.write(w)
+	default:
+		loghttp.ErrorWithRequest(w, r, httperror.MethodNotAllowed(fmt.Errorf("method %s is not allowed", r.Method)))
+	}
+}
+
+func (h *levelHandler) setLevel(level zapcore.Level, ttl time.Duration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if nil != h.timer {
+		h.timer.Stop()
+		h.timer = nil
+		h.revertAt = nil
+	}
+
+	h.level.SetLevel(level)
+	if ttl <= 0 {
+		h.baseLevel = level
+		return
+	}
+
+	revertAt := time.Now().Add(ttl)
+	h.revertAt = &revertAt
+	h.timer = time.AfterFunc(ttl, func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		if nil == h.revertAt || !h.revertAt.Equal(revertAt) {
+			return
+		}
+		h.level.SetLevel(h.baseLevel)
+		h.timer = nil
+		h.revertAt = nil
+	})
+}
+
+func (h *levelHandler) write(w http.ResponseWriter) {
+	h.lock.Lock()
+	level := h.level.Level()
+	payload := levelPayload{
+		Level:    &level,
+		RevertAt: h.revertAt,
+	}
+	h.lock.Unlock()
+
+	loghttp.Write(w, payload, http.StatusOK)
+}
@@ -2,6 +2,8 @@ package logger
 
 import (
 	"context"
+	"sync/atomic"
+
 	"github.com/gofrs/uuid"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -12,8 +14,29 @@ type loggerKeyType struct{}
 
 var loggerKey loggerKeyType
 
+var fallbackLogger atomic.Value
+
+// SetFallbackLogger set logger returned by GetLogger when the context doesn't have request scoped logger,
+// zap global logger is used by default
+func SetFallbackLogger(logger *zap.Logger) {
+	fallbackLogger.Store(logger)
+}
+
+func getFallbackLogger() *zap.Logger {
+	if logger, ok := fallbackLogger.Load().(*zap.Logger); ok && nil != logger {
+		return logger
+	}
+	return zap.L()
+}
+
 func GetLogger(ctx context.Context) *zap.Logger {
-	logger := ctx.Value(loggerKey).(*zap.Logger)
+	logger, ok := ctx.Value(loggerKey).(*zap.Logger)
+	if !ok {
+		logger = getFallbackLogger()
+	}
+	if fields := GetFields(ctx); 0 < len(fields) {
+		logger = logger.With(fields...)
+	}
 	spanCtx := trace.SpanContextFromContext(ctx)
 	if spanCtx.HasSpanID() {
 		logger = logger.With(
@@ -23,23 +46,66 @@ func GetLogger(ctx context.Context) *zap.Logger {
 	return logger
 }
 
+// HasLogger check whether the context has request scoped logger created by NewRequest
+func HasLogger(ctx context.Context) bool {
+	_, ok := ctx.Value(loggerKey).(*zap.Logger)
+	return ok
+}
+
 func NewRequest(ctx context.Context, logger *zap.Logger) context.Context {
+	return NewRequestWithID(ctx, logger, "")
+}
+
+// NewRequestWithID create request scoped logger and reuse given request id, new request id will be generated if
+// given request id is empty or invalid and there is no trace id in the context
+func NewRequestWithID(ctx context.Context, logger *zap.Logger, requestId string) context.Context {
+	return newRequest(ctx, logger, requestId, false)
+}
+
+func newRequest(ctx context.Context, logger *zap.Logger, requestId string, debug bool) context.Context {
+	if debug {
+		logger = escalateDebug(logger)
+	}
+
+	if !IsValidRequestID(requestId) {
+		requestId = ""
+	}
+
 	spanCtx := trace.SpanContextFromContext(ctx)
 	if spanCtx.HasTraceID() {
 		logger = logger.With(
 			zap.String("traceId", spanCtx.TraceID().String()),
 		)
 	}
-	if !spanCtx.HasTraceID() {
-		requestId, _ := uuid.NewV4()
+	if "" == requestId && !spanCtx.HasTraceID() {
+		newRequestId, _ := uuid.NewV4()
+		requestId = newRequestId.String()
+	}
+	if "" != requestId {
 		logger = logger.With(
-			zap.String("requestId", requestId.String()),
+			zap.String("requestId", requestId),
 		)
 	}
+	if "" == requestId {
+		requestId = spanCtx.TraceID().String()
+	}
+
+	// debug escalated request write every entry directly
+	if !debug {
+		ctx, logger = newRequestBuffer(ctx, logger)
+	}
+	ctx = newRequestFields(ctx)
+	ctx = context.WithValue(ctx, requestIDKey, requestId)
 	return context.WithValue(ctx, loggerKey, logger)
 }
 
 func New(env string, service string, options ...zap.Option) *zap.Logger {
+	logger, _ := NewWithLevel(env, service, options...)
+	return logger
+}
+
+// NewWithLevel create logger and return its atomic level, the level can be changed at runtime using LevelHandler
+func NewWithLevel(env string, service string, options ...zap.Option) (*zap.Logger, zap.AtomicLevel) {
 	cfg := zap.NewDevelopmentConfig()
 	if "development" != env {
 		cfg = zap.NewProductionConfig()
@@ -52,5 +118,5 @@ func New(env string, service string, options ...zap.Option) *zap.Logger {
 		zap.String("serviceName", service),
 	)
 
-	return logger
+	return logger, cfg.Level
 }
@@ -30,7 +30,7 @@ func DoGRPC(
 		}
 
 		if nil != after {
-			err = after(ctx, log, out)
+			err = after(ctx, GetLogger(ctx), out)
 		}
 		if nil != err {
 			return nil, err
@@ -55,7 +55,7 @@ func DoHTTP(
 			err = before(ctx, log, r)
 		}
 		if nil != err {
-			loghttp.Error(w, err)
+			loghttp.ErrorWithRequest(w, r, err)
 			return
 		}
 
@@ -64,10 +64,10 @@ func DoHTTP(
 		next(wr, r)
 
 		if nil != after {
-			err = after(ctx, log, wr.Body, wr.StatusCode)
+			err = after(ctx, GetLogger(ctx), wr.Body, wr.StatusCode)
 		}
 		if nil != err {
-			loghttp.Error(w, err)
+			loghttp.ErrorWithRequest(w, r, err)
 			return
 		}
 	}
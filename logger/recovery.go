@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gofrs/uuid"
+	"github.com/payfazz/fz-sentry/httperror"
+	"github.com/payfazz/fz-sentry/loghttp"
+	"go.uber.org/zap"
+)
+
+// HttpRecoveryMiddleware recover panic on http handler, log it with the stack trace and write internal server error
+// with reference id, should be used after HttpMiddleware
+func HttpRecoveryMiddleware() func(next http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			wr := loghttp.WrapWriter(w)
+
+			defer func() {
+				p := recover()
+				if nil == p {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				ctx := r.Context()
+				reference := RequestID(ctx)
+				if "" == reference {
+					id, _ := uuid.NewV4()
+					reference = id.String()
+				}
+
+				GetLogger(ctx).Error(
+					fmt.Sprintf("panic: %v", p),
+					zap.String("reference", reference),
+					zap.ByteString("panicStack", debug.Stack()),
+				)
+
+				if 0 != wr.StatusCode {
+					return
+				}
+
+				err := httperror.InternalServer(errors.New(fmt.Sprint(p)))
+				err.SetMessage(fmt.Sprintf("internal server error, reference: %s", reference))
+				loghttp.ErrorWithRequest(wr, r, err)
+			}()
+
+			next(wr, r)
+		}
+	}
+}
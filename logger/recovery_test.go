@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/payfazz/fz-sentry/loghttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecoveryProblem(t *testing.T) {
+	core, _ := observer.New(zap.InfoLevel)
+	handler := HttpMiddleware(zap.New(core))(HttpRecoveryMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	r.Header.Set("Accept", loghttp.PROBLEM_CONTENT_TYPE)
+	loghttp.SetErrorFormat(loghttp.FORMAT_NEGOTIATE)
+	defer loghttp.SetErrorFormat(loghttp.FORMAT_JSON)
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	var problem loghttp.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); nil != err {
+		t.Fatal(err)
+	}
+	if loghttp.PROBLEM_CONTENT_TYPE != w.Header().Get("Content-Type") || http.StatusInternalServerError != problem.Status {
+		t.Fatalf("panic should be written as problem details, got %s %s", w.Header().Get("Content-Type"), w.Body)
+	}
+	if "/orders" != problem.Instance || "" == problem.RequestID {
+		t.Errorf("problem should have instance and request id, got %+v", problem)
+	}
+}
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"github.com/payfazz/fz-sentry/redact"
+)
+
+// SetRedactor set redactor used by every payload middleware, GetZapPayloadField and alert sinks, should be called
+// once on start up, see redact.SetGlobal
+func SetRedactor(r *redact.Redactor) {
+	redact.SetGlobal(r)
+}
+
+// GetRedactor get redactor configured by SetRedactor, nil redactor will not redact anything
+func GetRedactor() *redact.Redactor {
+	return redact.Global()
+}
+
+func redactPayload(payload []byte) string {
+	return string(GetRedactor().Redact(payload))
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	REQUEST_ID_HEADER     = "X-Request-ID"
+	CORRELATION_ID_HEADER = "X-Correlation-ID"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9\-_.:+/=]{1,128}$`)
+
+var requestIDHeadersLock sync.RWMutex
+var requestIDHeaders = []string{REQUEST_ID_HEADER, CORRELATION_ID_HEADER}
+
+type requestIDKeyType struct{}
+
+var requestIDKey requestIDKeyType
+
+// SetRequestIDHeaders set header and grpc metadata keys used to read incoming request id, in priority order,
+// the first key is used to echo the request id back to the client
+func SetRequestIDHeaders(headers ...string) {
+	requestIDHeadersLock.Lock()
+	defer requestIDHeadersLock.Unlock()
+	requestIDHeaders = headers
+}
+
+func getRequestIDHeaders() []string {
+	requestIDHeadersLock.RLock()
+	defer requestIDHeadersLock.RUnlock()
+	return requestIDHeaders
+}
+
+// RequestIDHeader get header key used to echo and propagate request id
+func RequestIDHeader() string {
+	headers := getRequestIDHeaders()
+	if 0 == len(headers) {
+		return ""
+	}
+	return headers[0]
+}
+
+// RequestID get request id from context created by NewRequest
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// IsValidRequestID check whether incoming request id is safe to be reused
+func IsValidRequestID(requestID string) bool {
+	return requestIDPattern.MatchString(requestID)
+}
+
+// GetRequestIDFromHeader get first valid request id from configured headers
+func GetRequestIDFromHeader(header http.Header) string {
+	for _, key := range getRequestIDHeaders() {
+		if requestID := header.Get(key); IsValidRequestID(requestID) {
+			return requestID
+		}
+	}
+	return ""
+}
+
+// GetRequestIDFromMetadata get first valid request id from configured grpc incoming metadata keys
+func GetRequestIDFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, key := range getRequestIDHeaders() {
+		for _, requestID := range md.Get(key) {
+			if IsValidRequestID(requestID) {
+				return requestID
+			}
+		}
+	}
+	return ""
+}
+
+// SetResponseRequestID echo request id to http response header
+func SetResponseRequestID(w http.ResponseWriter, requestID string) {
+	header := RequestIDHeader()
+	if "" == header || "" == requestID {
+		return
+	}
+	w.Header().Set(header, requestID)
+}
+
+// ResponseRequestIDMetadata create grpc metadata to echo request id back to the client
+func ResponseRequestIDMetadata(requestID string) metadata.MD {
+	header := RequestIDHeader()
+	if "" == header || "" == requestID {
+		return nil
+	}
+	return metadata.Pairs(strings.ToLower(header), requestID)
+}
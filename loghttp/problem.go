@@ -0,0 +1,150 @@
+package loghttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/payfazz/fz-sentry/httperror"
+)
+
+type Format int32
+
+const (
+	FORMAT_JSON      Format = iota // httperror.Base JSON, ex: {"code": "Bad Request", "message": "..."}
+	FORMAT_PROBLEM                 // RFC 7807 problem details JSON
+	FORMAT_NEGOTIATE               // FORMAT_PROBLEM when request Accept header contain PROBLEM_CONTENT_TYPE, otherwise FORMAT_JSON
+)
+
+const (
+	PROBLEM_CONTENT_TYPE = "application/problem+json"
+	PROBLEM_DEFAULT_TYPE = "about:blank"
+)
+
+var errorFormat int32
+
+// SetErrorFormat set default error response format used by Error, should be called once on start up
+func SetErrorFormat(format Format) {
+	atomic.StoreInt32(&errorFormat, int32(format))
+}
+
+// GetErrorFormat get default error response format configured by SetErrorFormat
+func GetErrorFormat() Format {
+	return Format(atomic.LoadInt32(&errorFormat))
+}
+
+// ErrorOptions is request information used by ErrorWithRequest, it is attached to the request context by
+// logger.HttpMiddleware and ErrorFormatMiddleware
+type ErrorOptions struct {
+	Format    Format
+	Accept    string // request Accept header, used by FORMAT_NEGOTIATE
+	Instance  string // request path
+	RequestID string
+}
+
+// Problem is RFC 7807 problem details with business code, request id and validation errors extension members
+type Problem struct {
+	Type      string                    `json:"type"`
+	Title     string                    `json:"title"`
+	Status    int                       `json:"status"`
+	Detail    string                    `json:"detail,omitempty"`
+	Instance  string                    `json:"instance,omitempty"`
+	ErrorCode string                    `json:"errorCode,omitempty"`
+	RequestID string                    `json:"requestId,omitempty"`
+	Errors    httperror.ValidationError `json:"errors,omitempty"`
+}
+
+// NewProblem create problem details from http error
+func NewProblem(err httperror.Interface, instance string, requestID string) *Problem {
+	problem := &Problem{
+		Type:      PROBLEM_DEFAULT_TYPE,
+		Title:     http.StatusText(err.GetCode()),
+		Status:    err.GetCode(),
+		Detail:    err.Error(),
+		Instance:  instance,
+		RequestID: requestID,
+	}
+
+	var base *httperror.Base
+	if errors.As(err, &base) {
+		problem.ErrorCode = base.ErrorCode
+	}
+
+	var validation httperror.ValidationError
+	if errors.As(err, &validation) {
+		problem.Errors = validation
+	}
+
+	return problem
+}
+
+// ErrorFormatMiddleware set error response format of Error for every request of the server, overriding SetErrorFormat
+func ErrorFormatMiddleware(format Format) func(next http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			options := GetErrorOptions(r)
+			options.Format = format
+
+			next(w, r.WithContext(WithErrorOptions(r.Context(), options)))
+		}
+	}
+}
+
+type errorOptionsKeyType struct{}
+
+var errorOptionsKey errorOptionsKeyType
+
+// NewErrorOptions create error options of the request with default error format
+func NewErrorOptions(r *http.Request) ErrorOptions {
+	return ErrorOptions{
+		Format:   GetErrorFormat(),
+		Accept:   r.Header.Get("Accept"),
+		Instance: r.URL.Path,
+	}
+}
+
+// WithErrorOptions attach error options to the context, the options are kept when the response writer is wrapped by
+// other middlewares
+func WithErrorOptions(ctx context.Context, options ErrorOptions) context.Context {
+	return context.WithValue(ctx, errorOptionsKey, options)
+}
+
+// GetErrorOptions get error options attached to the request context, default to NewErrorOptions
+func GetErrorOptions(r *http.Request) ErrorOptions {
+	if options, ok := r.Context().Value(errorOptionsKey).(ErrorOptions); ok {
+		return options
+	}
+	return NewErrorOptions(r)
+}
+
+func (o ErrorOptions) problem() bool {
+	switch o.Format {
+	case FORMAT_PROBLEM:
+		return true
+	case FORMAT_NEGOTIATE:
+		return acceptProblem(o.Accept)
+	default:
+		return false
+	}
+}
+
+func acceptProblem(accept string) bool {
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if nil == err && PROBLEM_CONTENT_TYPE == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func writeProblem(w http.ResponseWriter, problem *Problem) {
+	w.Header().Set("Content-Type", PROBLEM_CONTENT_TYPE)
+	w.WriteHeader(problem.Status)
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
@@ -7,8 +7,29 @@ import (
 	"github.com/payfazz/fz-sentry/httperror"
 )
 
+// Error write http error response with format configured by SetErrorFormat, FORMAT_NEGOTIATE is written as
+// FORMAT_JSON because the request is not available, use ErrorWithRequest to respect the request options
 func Error(w http.ResponseWriter, err error) {
+	writeError(w, err, ErrorOptions{Format: GetErrorFormat()})
+}
+
+// ErrorWithRequest write http error response with format selected by ErrorOptions attached to the request context
+func ErrorWithRequest(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, err, GetErrorOptions(r))
+}
+
+func writeError(w http.ResponseWriter, err error, options ErrorOptions) {
+	if writer, ok := w.(*Writer); ok {
+		writer.Err = err
+	}
+
 	be := httperror.GetInstance(err)
+
+	if options.problem() {
+		writeProblem(w, NewProblem(be, options.Instance, options.RequestID))
+		return
+	}
+
 	Write(w, be, be.GetCode())
 }
 
@@ -9,6 +9,8 @@ type Writer struct {
 	http.ResponseWriter
 	Body       []byte
 	StatusCode int
+	Size       int
+	Err        error // error written by Error or ErrorWithRequest
 }
 
 func (w *Writer) Code() string {
@@ -16,8 +18,13 @@ func (w *Writer) Code() string {
 }
 
 func (w *Writer) Write(body []byte) (int, error) {
+	if 0 == w.StatusCode {
+		w.StatusCode = http.StatusOK
+	}
 	w.Body = body
-	return w.ResponseWriter.Write(body)
+	n, err := w.ResponseWriter.Write(body)
+	w.Size += n
+	return n, err
 }
 
 func (w *Writer) WriteHeader(statusCode int) {
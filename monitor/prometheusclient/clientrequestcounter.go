@@ -0,0 +1,43 @@
+package prometheusclient
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var clientCounterOnce sync.Once
+var clientCounter *prometheus.CounterVec
+
+func clientRequestCounter() *prometheus.CounterVec {
+	clientCounterOnce.Do(func() {
+		clientCounter = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "_client_requests_total",
+				Help: "A counter for outgoing requests to upstream services.",
+			},
+			[]string{"upstream", "path", "method", "code"},
+		)
+
+		prometheus.MustRegister(clientCounter)
+	})
+
+	return clientCounter
+}
+
+// IncrementClientRequestCounter increment outgoing request count to upstream service, usage example can be seen in
+// `httpclient` package
+// required params:
+// - upstream: your upstream service name or host
+// - pattern: upstream route pattern not the requested url, ex: `/v1/users/:id` (correct); `/v1/users/1` (incorrect)
+// - method: your request method (GET, POST, PATCH, etc)
+// - code: upstream status code (200, 404, 500, etc), or `error` on transport error
+func IncrementClientRequestCounter(upstream string, pattern string, method string, code string) {
+	labels := prometheus.Labels{
+		"upstream": upstream,
+		"path":     pattern,
+		"method":   method,
+		"code":     code,
+	}
+	clientRequestCounter().With(labels).Inc()
+}
@@ -0,0 +1,41 @@
+package prometheusclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var clientDurationHistogramOnce sync.Once
+var clientDurationHistogram *prometheus.HistogramVec
+
+func clientRequestDurationHistogram() *prometheus.HistogramVec {
+	clientDurationHistogramOnce.Do(func() {
+		clientDurationHistogram = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "_client_request_duration_seconds",
+				Help:    "A histogram of latencies for outgoing requests to upstream services in second.",
+				Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 5, 10},
+			},
+			[]string{"upstream", "path", "method", "code"},
+		)
+
+		prometheus.MustRegister(clientDurationHistogram)
+	})
+
+	return clientDurationHistogram
+}
+
+// ObserveClientRequestDuration observe outgoing request duration from startRequestAt until now and store it as histogram,
+// params are the same with IncrementClientRequestCounter
+func ObserveClientRequestDuration(upstream string, pattern string, method string, code string, startRequestAt time.Time) {
+	labels := prometheus.Labels{
+		"upstream": upstream,
+		"path":     pattern,
+		"method":   method,
+		"code":     code,
+	}
+	duration := float64(time.Since(startRequestAt).Seconds())
+	clientRequestDurationHistogram().With(labels).Observe(duration)
+}
@@ -0,0 +1,16 @@
+package redact
+
+import "sync/atomic"
+
+var global atomic.Value
+
+// SetGlobal set redactor shared by logger payload middlewares and alert sinks, should be called once on start up
+func SetGlobal(r *Redactor) {
+	global.Store(r)
+}
+
+// Global get redactor configured by SetGlobal, nil redactor will not redact anything
+func Global() *Redactor {
+	r, _ := global.Load().(*Redactor)
+	return r
+}
@@ -0,0 +1,149 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Redactor mask sensitive values from a payload before it is logged
+type Redactor struct {
+	hashKey []byte
+	rules   []Rule
+}
+
+// New create a redactor, hashKey is used as HMAC key for rules with MASK_HASH mode and is required when any rule
+// use MASK_HASH, since unkeyed hash of short value like PIN can be brute forced
+func New(hashKey []byte, rules ...Rule) (*Redactor, error) {
+	for _, rule := range rules {
+		if MASK_HASH == rule.Mode && 0 == len(hashKey) {
+			return nil, errors.New("redact: hash key is required for MASK_HASH rule")
+		}
+	}
+
+	return &Redactor{
+		hashKey: hashKey,
+		rules:   rules,
+	}, nil
+}
+
+// Redact redact JSON payload, non JSON payload will only be redacted by Rule.Value
+func (r *Redactor) Redact(payload []byte) []byte {
+	if nil == r || 0 == len(r.rules) || 0 == len(payload) {
+		return payload
+	}
+
+	var data interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); nil != err || decoder.More() {
+		return []byte(r.redactValue(string(payload)))
+	}
+
+	result, err := marshal(r.walk(data, nil))
+	if nil != err {
+		return []byte(r.redactValue(string(payload)))
+	}
+
+	return result
+}
+
+// RedactValue marshal given value to JSON and redact it
+func (r *Redactor) RedactValue(value interface{}) []byte {
+	payload, _ := json.Marshal(value)
+	return r.Redact(payload)
+}
+
+func (r *Redactor) walk(data interface{}, path []string) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			childPath := append(path[:len(path):len(path)], key)
+			if rule, ok := r.matchRule(childPath); ok {
+				value[key] = rule.mask(stringify(child), r.hashKey)
+				continue
+			}
+			value[key] = r.walk(child, childPath)
+		}
+		return value
+	case []interface{}:
+		childPath := append(path[:len(path):len(path)], arraySegment)
+		for i, child := range value {
+			if !isContainer(child) {
+				if rule, ok := r.matchRule(childPath); ok {
+					value[i] = rule.mask(stringify(child), r.hashKey)
+					continue
+				}
+			}
+			value[i] = r.walk(child, childPath)
+		}
+		return value
+	case string:
+		return r.redactValue(value)
+	case json.Number:
+		if redacted := r.redactValue(value.String()); redacted != value.String() {
+			return redacted
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) matchRule(path []string) (Rule, bool) {
+	for _, rule := range r.rules {
+		if rule.matchPath(path) || rule.matchKey(path) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (r *Redactor) redactValue(value string) string {
+	for _, rule := range r.rules {
+		if nil == rule.Value {
+			continue
+		}
+		value = rule.Value.ReplaceAllStringFunc(value, func(match string) string {
+			return rule.mask(match, r.hashKey)
+		})
+	}
+	return value
+}
+
+// isContainer check whether the value is JSON object or array, their elements are matched on the next level
+func isContainer(data interface{}) bool {
+	switch data.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+func stringify(data interface{}) string {
+	switch value := data.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case nil:
+		return ""
+	}
+
+	by, err := marshal(data)
+	if nil != err {
+		return fmt.Sprint(data)
+	}
+	return string(by)
+}
+
+func marshal(data interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); nil != err {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,185 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"testing"
+)
+
+func hash(key string, value string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []Rule
+		payload  string
+		expected string
+	}{
+		{
+			name:     "path",
+			rules:    []Rule{{Path: "card.number"}},
+			payload:  `{"card":{"number":"4111111111111111","holder":"john"}}`,
+			expected: `{"card":{"holder":"john","number":"****"}}`,
+		},
+		{
+			name:     "path does not match other depth",
+			rules:    []Rule{{Path: "number"}},
+			payload:  `{"card":{"number":"4111111111111111"}}`,
+			expected: `{"card":{"number":"4111111111111111"}}`,
+		},
+		{
+			name:     "wildcard key",
+			rules:    []Rule{{Path: "*.pin"}},
+			payload:  `{"debit":{"pin":"123456"},"credit":{"pin":"654321"}}`,
+			expected: `{"credit":{"pin":"****"},"debit":{"pin":"****"}}`,
+		},
+		{
+			name:     "wildcard array element",
+			rules:    []Rule{{Path: "items.*.pin"}},
+			payload:  `{"items":[{"pin":"123456"},{"pin":"654321","name":"x"}]}`,
+			expected: `{"items":[{"pin":"****"},{"name":"x","pin":"****"}]}`,
+		},
+		{
+			name:     "transparent array",
+			rules:    []Rule{{Path: "items.pin"}},
+			payload:  `{"items":[{"pin":"123456"}]}`,
+			expected: `{"items":[{"pin":"****"}]}`,
+		},
+		{
+			name:     "nested array",
+			rules:    []Rule{{Path: "matrix.*.*.pin"}},
+			payload:  `{"matrix":[[{"pin":"1"}],[{"pin":"2"}]]}`,
+			expected: `{"matrix":[[{"pin":"****"}],[{"pin":"****"}]]}`,
+		},
+		{
+			name:     "root array",
+			rules:    []Rule{{Path: "*.pin"}},
+			payload:  `[{"pin":"123456"}]`,
+			expected: `[{"pin":"****"}]`,
+		},
+		{
+			name:     "scalar array element",
+			rules:    []Rule{{Path: "cards.*", Mode: MASK_PARTIAL}},
+			payload:  `{"cards":["4111111111111111","5500000000000004"],"name":"john"}`,
+			expected: `{"cards":["****1111","****0004"],"name":"john"}`,
+		},
+		{
+			name:     "root scalar array",
+			rules:    []Rule{{Path: "*"}},
+			payload:  `["4111111111111111",1234]`,
+			expected: `["****","****"]`,
+		},
+		{
+			name:     "key does not match array element",
+			rules:    []Rule{{Key: regexp.MustCompile(`.`)}},
+			payload:  `[1,2]`,
+			expected: `[1,2]`,
+		},
+		{
+			name:     "key in any depth",
+			rules:    []Rule{{Key: regexp.MustCompile(`(?i)^password$`)}},
+			payload:  `{"Password":"secret","user":{"password":"secret","name":"john"}}`,
+			expected: `{"Password":"****","user":{"name":"john","password":"****"}}`,
+		},
+		{
+			name:     "object value",
+			rules:    []Rule{{Path: "card"}},
+			payload:  `{"card":{"number":"4111111111111111"}}`,
+			expected: `{"card":"****"}`,
+		},
+		{
+			name:     "partial",
+			rules:    []Rule{{Path: "card", Mode: MASK_PARTIAL}},
+			payload:  `{"card":"4111111111111111"}`,
+			expected: `{"card":"****1111"}`,
+		},
+		{
+			name:     "partial keep",
+			rules:    []Rule{{Path: "phone", Mode: MASK_PARTIAL, Keep: 2}},
+			payload:  `{"phone":"08123456789"}`,
+			expected: `{"phone":"****89"}`,
+		},
+		{
+			name:     "partial shorter than keep",
+			rules:    []Rule{{Path: "pin", Mode: MASK_PARTIAL}},
+			payload:  `{"pin":"123"}`,
+			expected: `{"pin":"****"}`,
+		},
+		{
+			name:     "partial number",
+			rules:    []Rule{{Path: "account", Mode: MASK_PARTIAL}},
+			payload:  `{"account":1234567890}`,
+			expected: `{"account":"****7890"}`,
+		},
+		{
+			name:     "hash",
+			rules:    []Rule{{Path: "email", Mode: MASK_HASH}},
+			payload:  `{"email":"john@example.com"}`,
+			expected: `{"email":"` + hash("key", "john@example.com") + `"}`,
+		},
+		{
+			name:     "value pattern",
+			rules:    []Rule{{Value: regexp.MustCompile(`\d{16}`), Mode: MASK_PARTIAL}},
+			payload:  `{"note":"card 4111111111111111 declined"}`,
+			expected: `{"note":"card ****1111 declined"}`,
+		},
+		{
+			name:     "value pattern on non JSON payload",
+			rules:    []Rule{{Value: regexp.MustCompile(`\d{16}`)}},
+			payload:  `card=4111111111111111`,
+			expected: `card=****`,
+		},
+		{
+			name:     "number is kept",
+			rules:    []Rule{{Path: "pin"}},
+			payload:  `{"amount":10000.50}`,
+			expected: `{"amount":10000.50}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := New([]byte("key"), test.rules...)
+			if nil != err {
+				t.Fatal(err)
+			}
+			if result := string(r.Redact([]byte(test.payload))); test.expected != result {
+				t.Errorf("expected %s, got %s", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestRedactNil(t *testing.T) {
+	var r *Redactor
+	if result := string(r.Redact([]byte(`{"pin":"123456"}`))); `{"pin":"123456"}` != result {
+		t.Errorf("nil redactor should not change payload, got %s", result)
+	}
+}
+
+func TestRedactValue(t *testing.T) {
+	r, err := New(nil, Rule{Path: "pin"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	result := string(r.RedactValue(struct {
+		Pin string `json:"pin"`
+	}{Pin: "123456"}))
+	if `{"pin":"****"}` != result {
+		t.Errorf("unexpected result: %s", result)
+	}
+}
+
+func TestNewHashWithoutKey(t *testing.T) {
+	for _, key := range [][]byte{nil, {}} {
+		if _, err := New(key, Rule{Path: "pin", Mode: MASK_HASH}); nil == err {
+			t.Error("MASK_HASH without hash key should be rejected")
+		}
+	}
+}
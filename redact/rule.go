@@ -0,0 +1,94 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type Mode int
+
+const (
+	MASK_FULL    Mode = iota // replace the whole value with MASK
+	MASK_PARTIAL             // keep the last Rule.Keep characters, ex: `****1234`
+	MASK_HASH                // replace the value with hex encoded HMAC-SHA256 of the value
+)
+
+const (
+	MASK         = "****"
+	DEFAULT_KEEP = 4
+)
+
+// arraySegment mark array level on walked path
+const arraySegment = "\x00"
+
+// Rule describe which value should be redacted and how to redact it, a value is matched when any of
+// Path, Key or Value is matched
+// - Path: dot separated JSON path, `*` match any key or array element, ex: `card.number`; `items.*.pin`
+// - Key: pattern for key name in any depth, ex: `(?i)^(password|pin)$`
+// - Value: pattern for string value, only the matched part of the value will be redacted
+// array can also be traversed transparently, ex: `items.pin` also match `{"items": [{"pin": "123456"}]}`
+type Rule struct {
+	Path  string
+	Key   *regexp.Regexp
+	Value *regexp.Regexp
+	Mode  Mode
+	Keep  int
+}
+
+func (r Rule) matchPath(path []string) bool {
+	if "" == r.Path {
+		return false
+	}
+
+	return matchSegments(strings.Split(r.Path, "."), path)
+}
+
+func matchSegments(segments []string, path []string) bool {
+	if 0 == len(path) {
+		return 0 == len(segments)
+	}
+
+	if arraySegment == path[0] {
+		if matchSegments(segments, path[1:]) {
+			return true
+		}
+		return len(segments) > 0 && "*" == segments[0] && matchSegments(segments[1:], path[1:])
+	}
+
+	if 0 == len(segments) || ("*" != segments[0] && segments[0] != path[0]) {
+		return false
+	}
+	return matchSegments(segments[1:], path[1:])
+}
+
+func (r Rule) matchKey(path []string) bool {
+	if nil == r.Key || 0 == len(path) || arraySegment == path[len(path)-1] {
+		return false
+	}
+	return r.Key.MatchString(path[len(path)-1])
+}
+
+func (r Rule) mask(value string, hashKey []byte) string {
+	switch r.Mode {
+	case MASK_PARTIAL:
+		keep := r.Keep
+		if keep <= 0 {
+			keep = DEFAULT_KEEP
+		}
+		if utf8.RuneCountInString(value) <= keep {
+			return MASK
+		}
+		runes := []rune(value)
+		return MASK + string(runes[len(runes)-keep:])
+	case MASK_HASH:
+		mac := hmac.New(sha256.New, hashKey)
+		_, _ = mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))
+	default:
+		return MASK
+	}
+}
@@ -0,0 +1,291 @@
+package sentrycore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	CLIENT_NAME           = "fz-sentry/1.0"
+	DEFAULT_TIMEOUT       = 5 * time.Second
+	DEFAULT_QUEUE_SIZE    = 100
+	DEFAULT_FLUSH_TIMEOUT = 5 * time.Second
+)
+
+type Options struct {
+	Environment  string
+	Release      string
+	ServerName   string
+	RateLimit    int // max events sent per minute, 0 means unlimited
+	Fingerprint  func(ent zapcore.Entry, fields map[string]interface{}) []string
+	Silences     *alert.Silences // entries matched by an active silence are not sent
+	QueueSize    int             // max queued events, new event is dropped when the queue is full, default to DEFAULT_QUEUE_SIZE
+	FlushTimeout time.Duration   // max time Sync waits for queued events, default to DEFAULT_FLUSH_TIMEOUT
+	HTTPClient   *http.Client
+}
+
+type client struct {
+	dsn     *dsn
+	options Options
+	queue   chan queued
+	errors  alert.Errors
+	stop    chan struct{}
+
+	lock          sync.Mutex
+	closed        bool
+	windowStart   time.Time
+	windowCount   int
+	disabledUntil time.Time
+}
+
+// queued is event waiting to be sent, flush marker has nil event and done channel closed when it is reached
+type queued struct {
+	event *event
+	done  chan struct{}
+}
+
+type sentryCore struct {
+	zapcore.LevelEnabler
+
+	client *client
+	fields []zapcore.Field
+}
+
+// NewWrapper create zap core wrapper that send entries at or above minLevel to sentry as envelope event,
+// error is returned if dsn is invalid
+func NewWrapper(dsn string, minLevel zapcore.Level, options Options) (func(zapcore.Core) zapcore.Core, error) {
+	sc, err := New(dsn, minLevel, options)
+	if nil != err {
+		return nil, err
+	}
+	return func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, sc)
+	}, nil
+}
+
+// New create standalone sentry core, events are sent asynchronously by background worker, Sync wait for queued
+// events and delivery errors are returned from the next Write or Sync so zap write them to its ErrorOutput, the core
+// implements io.Closer to stop the background worker
+func New(dsn string, minLevel zapcore.Level, options Options) (zapcore.Core, error) {
+	d, err := parseDSN(dsn)
+	if nil != err {
+		return nil, err
+	}
+
+	if nil == options.HTTPClient {
+		options.HTTPClient = &http.Client{Timeout: DEFAULT_TIMEOUT}
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DEFAULT_QUEUE_SIZE
+	}
+	if options.FlushTimeout <= 0 {
+		options.FlushTimeout = DEFAULT_FLUSH_TIMEOUT
+	}
+
+	c := &client{
+		dsn:     d,
+		options: options,
+		queue:   make(chan queued, options.QueueSize),
+		stop:    make(chan struct{}),
+	}
+	go c.run()
+
+	return &sentryCore{
+		LevelEnabler: minLevel,
+		client:       c,
+	}, nil
+}
+
+func (c *sentryCore) With(fields []zapcore.Field) zapcore.Core {
+	return &sentryCore{
+		LevelEnabler: c.LevelEnabler,
+		client:       c.client,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *sentryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sentryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.Enabled(ent.Level) {
+		return nil
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	var cause error
+	for _, field := range append(c.fields[:len(c.fields):len(c.fields)], fields...) {
+		field.AddTo(enc)
+		if err, ok := field.Interface.(error); ok && zapcore.ErrorType == field.Type {
+			cause = err
+		}
+	}
+
+	if c.client.options.Silences.Silenced(alert.Alert{Entry: ent, Fields: enc.Fields}, time.Now()) || !c.client.allow(ent.Time) {
+		return c.client.errors.Take()
+	}
+
+	c.client.enqueue(newEvent(ent, enc.Fields, cause, c.client.options))
+
+	// process may exit or panic right after fatal and panic entries, wait for the delivery like zap ioCore
+	if ent.Level > zapcore.ErrorLevel {
+		return multierr.Append(c.client.flush(), c.client.errors.Take())
+	}
+	return c.client.errors.Take()
+}
+
+// Sync wait for queued events and return delivery errors
+func (c *sentryCore) Sync() error {
+	return multierr.Append(c.client.flush(), c.client.errors.Take())
+}
+
+// Close wait for queued events and stop the background worker, entries written after Close are dropped
+func (c *sentryCore) Close() error {
+	return multierr.Append(c.client.close(), c.client.errors.Take())
+}
+
+func (c *client) enqueue(e *event) {
+	if c.isClosed() {
+		return
+	}
+
+	select {
+	case c.queue <- queued{event: e}:
+	default:
+		c.errors.Add(errors.New("sentry queue is full, event is dropped"))
+	}
+}
+
+func (c *client) run() {
+	for {
+		select {
+		case q := <-c.queue:
+			if nil != q.done {
+				close(q.done)
+				continue
+			}
+			c.errors.Add(c.send(q.event))
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// flush wait until every event queued before the call is sent or FlushTimeout is reached
+func (c *client) flush() error {
+	if c.isClosed() {
+		return nil
+	}
+
+	timeout := time.NewTimer(c.options.FlushTimeout)
+	defer timeout.Stop()
+
+	done := make(chan struct{})
+	select {
+	case c.queue <- queued{done: done}:
+	case <-timeout.C:
+		return errors.New("sentry flush timeout")
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-timeout.C:
+		return errors.New("sentry flush timeout")
+	}
+}
+
+// close flush queued events and stop the worker
+func (c *client) close() error {
+	err := c.flush()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.stop)
+	}
+	return err
+}
+
+func (c *client) isClosed() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.closed
+}
+
+func (c *client) allow(now time.Time) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if now.Before(c.disabledUntil) {
+		return false
+	}
+	if c.options.RateLimit <= 0 {
+		return true
+	}
+
+	if now.Sub(c.windowStart) >= time.Minute {
+		c.windowStart = now
+		c.windowCount = 0
+	}
+	if c.windowCount >= c.options.RateLimit {
+		return false
+	}
+	c.windowCount++
+
+	return true
+}
+
+func (c *client) send(e *event) error {
+	body, err := e.envelope(c.dsn)
+	if nil != err {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.dsn.endpoint, bytes.NewReader(body))
+	if nil != err {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", c.dsn.authHeader())
+
+	resp, err := c.options.HTTPClient.Do(req)
+	if nil != err {
+		return fmt.Errorf("send event to sentry: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if http.StatusTooManyRequests == resp.StatusCode {
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if nil != err || retryAfter <= 0 {
+			retryAfter = 60
+		}
+		c.lock.Lock()
+		c.disabledUntil = time.Now().Add(time.Duration(retryAfter) * time.Second)
+		c.lock.Unlock()
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("send event to sentry: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
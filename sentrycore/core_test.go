@@ -0,0 +1,288 @@
+package sentrycore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"github.com/payfazz/fz-sentry/alert/alerttest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stub is sentry ingestion server that record received events
+type stub struct {
+	*httptest.Server
+
+	lock    sync.Mutex
+	events  []event
+	headers []http.Header
+	status  int
+	block   chan struct{}
+}
+
+func newStub(t *testing.T) *stub {
+	s := &stub{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if nil != s.block {
+			<-s.block
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+		if 3 != len(lines) {
+			t.Errorf("expected envelope with 3 lines, got %d", len(lines))
+			return
+		}
+
+		var e event
+		if err := json.Unmarshal(lines[2], &e); nil != err {
+			t.Errorf("invalid event payload: %v", err)
+			return
+		}
+
+		s.lock.Lock()
+		s.events = append(s.events, e)
+		s.headers = append(s.headers, r.Header)
+		status := s.status
+		s.lock.Unlock()
+
+		if http.StatusTooManyRequests == status {
+			w.Header().Set("Retry-After", "60")
+		}
+		w.WriteHeader(status)
+	}))
+	return s
+}
+
+func (s *stub) dsn() string {
+	return strings.Replace(s.URL, "http://", "http://public@", 1) + "/42"
+}
+
+func (s *stub) received() []event {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]event(nil), s.events...)
+}
+
+func TestCore(t *testing.T) {
+	var stubs []*stub
+	defer func() {
+		for _, s := range stubs {
+			s.Close()
+		}
+	}()
+
+	alerttest.TestCore(t, func(t *testing.T, minLevel zapcore.Level) (zapcore.Core, func() []alert.Alert) {
+		s := newStub(t)
+		stubs = append(stubs, s)
+
+		core, err := New(s.dsn(), minLevel, Options{})
+		if nil != err {
+			t.Fatal(err)
+		}
+
+		return core, func() []alert.Alert {
+			var alerts []alert.Alert
+			for _, e := range s.received() {
+				fields := map[string]interface{}{}
+				for k, v := range e.Extra {
+					fields[k] = v
+				}
+				for k, v := range e.Tags {
+					fields[k] = v
+				}
+
+				level := zapcore.ErrorLevel
+				for l, name := range levels {
+					if name == e.Level && l < level {
+						level = l
+					}
+				}
+
+				alerts = append(alerts, alert.Alert{
+					Entry:  zapcore.Entry{Level: level, Message: e.Message.Formatted},
+					Fields: fields,
+				})
+			}
+			return alerts
+		}
+	})
+}
+
+func TestNewInvalidDSN(t *testing.T) {
+	for _, dsn := range []string{"", "http://host/42", "http://key@host/"} {
+		if _, err := New(dsn, zapcore.ErrorLevel, Options{}); nil == err {
+			t.Errorf("expected error for dsn %q", dsn)
+		}
+		if _, err := NewWrapper(dsn, zapcore.ErrorLevel, Options{}); nil == err {
+			t.Errorf("expected wrapper error for dsn %q", dsn)
+		}
+	}
+}
+
+func TestWriteSendEnvelope(t *testing.T) {
+	s := newStub(t)
+	defer s.Close()
+	core, err := New(s.dsn(), zapcore.ErrorLevel, Options{Environment: "test", Release: "v1"})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	logger := zap.New(core, zap.AddStacktrace(zapcore.ErrorLevel))
+	logger.Warn("ignored")
+	logger.Error("query failed", zap.Error(&os.PathError{Op: "open", Path: "/tmp/x", Err: errors.New("denied")}), zap.String("requestId", "req-1"))
+	if err := logger.Sync(); nil != err {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+
+	events := s.received()
+	if 1 != len(events) {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	e := events[0]
+	if "query failed" != e.Message.Formatted || "error" != e.Level || "test" != e.Environment || "v1" != e.Release {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if "req-1" != e.Tags["requestId"] {
+		t.Errorf("requestId should be sent as tag, got %v", e.Tags)
+	}
+	if nil == e.Exception || 1 != len(e.Exception.Values) {
+		t.Fatalf("expected exception, got %+v", e.Exception)
+	}
+	if "*errors.errorString" != e.Exception.Values[0].Type {
+		t.Errorf("exception type should be the error type, got %s", e.Exception.Values[0].Type)
+	}
+	if 0 == len(e.Exception.Values[0].Stacktrace.Frames) {
+		t.Error("exception should contain stack frames")
+	}
+
+	s.lock.Lock()
+	auth := s.headers[0].Get("X-Sentry-Auth")
+	s.lock.Unlock()
+	if !strings.Contains(auth, "sentry_key=public") {
+		t.Errorf("unexpected auth header: %s", auth)
+	}
+}
+
+func TestWriteDoesNotBlock(t *testing.T) {
+	s := newStub(t)
+	defer s.Close()
+	s.block = make(chan struct{})
+
+	core, err := New(s.dsn(), zapcore.ErrorLevel, Options{})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "boom"}, nil); nil != err {
+			t.Errorf("Write returned error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Write should not wait for sentry, took %s", elapsed)
+	}
+
+	close(s.block)
+	if err := core.Sync(); nil != err {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if 5 != len(s.received()) {
+		t.Errorf("Sync should drain the queue, got %d events", len(s.received()))
+	}
+}
+
+func TestSyncFlushTimeout(t *testing.T) {
+	s := newStub(t)
+	defer s.Close()
+	s.block = make(chan struct{})
+	defer close(s.block)
+
+	core, err := New(s.dsn(), zapcore.ErrorLevel, Options{FlushTimeout: 50 * time.Millisecond})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "boom"}, nil)
+	if err := core.Sync(); nil == err {
+		t.Error("Sync should return error on flush timeout")
+	}
+}
+
+func TestDeliveryError(t *testing.T) {
+	s := newStub(t)
+	defer s.Close()
+	s.status = http.StatusTooManyRequests
+
+	core, err := New(s.dsn(), zapcore.ErrorLevel, Options{})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "first"}, nil)
+	if err := core.Sync(); nil == err {
+		t.Error("Sync should return delivery error")
+	}
+
+	// rate limited by Retry-After
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "second"}, nil)
+	if err := core.Sync(); nil != err {
+		t.Errorf("Sync returned error: %v", err)
+	}
+	if 1 != len(s.received()) {
+		t.Errorf("event should not be sent while rate limited, got %d events", len(s.received()))
+	}
+}
+
+func TestWriteFlushFatal(t *testing.T) {
+	s := newStub(t)
+	defer s.Close()
+
+	core, err := New(s.dsn(), zapcore.ErrorLevel, Options{})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "error"}, nil)
+	if err := core.Write(zapcore.Entry{Level: zapcore.PanicLevel, Time: time.Now(), Message: "panic"}, nil); nil != err {
+		t.Fatal(err)
+	}
+	if 2 != len(s.received()) {
+		t.Errorf("panic entry should wait for queued events, got %d events", len(s.received()))
+	}
+}
+
+func TestClose(t *testing.T) {
+	s := newStub(t)
+	defer s.Close()
+
+	core, err := New(s.dsn(), zapcore.ErrorLevel, Options{})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "before close"}, nil)
+	if err := core.(io.Closer).Close(); nil != err {
+		t.Fatal(err)
+	}
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: "after close"}, nil)
+	if err := core.Sync(); nil != err {
+		t.Errorf("Sync after Close should not fail, got %v", err)
+	}
+	if events := s.received(); 1 != len(events) || "before close" != events[0].Message.Formatted {
+		t.Errorf("only events before Close should be sent, got %v", events)
+	}
+}
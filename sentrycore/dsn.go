@@ -0,0 +1,48 @@
+package sentrycore
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type dsn struct {
+	raw       string
+	publicKey string
+	endpoint  string
+}
+
+// parseDSN parse sentry dsn, ex: `https://<public key>@<host>/<project id>`
+func parseDSN(raw string) (*dsn, error) {
+	u, err := url.Parse(raw)
+	if nil != err {
+		return nil, err
+	}
+
+	if nil == u.User || "" == u.User.Username() {
+		return nil, errors.New("sentry dsn: missing public key")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	projectID := path[idx+1:]
+	if "" == projectID {
+		return nil, errors.New("sentry dsn: missing project id")
+	}
+
+	prefix := ""
+	if idx > 0 {
+		prefix = "/" + path[:idx]
+	}
+
+	return &dsn{
+		raw:       raw,
+		publicKey: u.User.Username(),
+		endpoint:  fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, projectID),
+	}, nil
+}
+
+func (d *dsn) authHeader() string {
+	return fmt.Sprintf("Sentry sentry_version=7, sentry_key=%s, sentry_client=%s", d.publicKey, CLIENT_NAME)
+}
@@ -0,0 +1,208 @@
+package sentrycore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap/zapcore"
+)
+
+// fields moved from extra to tags
+var tagKeys = []string{"serviceName", "requestId", "traceId", "spanId"}
+
+type event struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Logger      string                 `json:"logger,omitempty"`
+	Platform    string                 `json:"platform"`
+	Message     *message               `json:"message,omitempty"`
+	Culprit     string                 `json:"culprit,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Fingerprint []string               `json:"fingerprint,omitempty"`
+	Exception   *exceptions            `json:"exception,omitempty"`
+	Contexts    map[string]interface{} `json:"contexts,omitempty"`
+}
+
+type message struct {
+	Formatted string `json:"formatted"`
+}
+
+type exceptions struct {
+	Values []exception `json:"values"`
+}
+
+type exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *stacktrace `json:"stacktrace,omitempty"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+var levels = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "debug",
+	zapcore.InfoLevel:   "info",
+	zapcore.WarnLevel:   "warning",
+	zapcore.ErrorLevel:  "error",
+	zapcore.DPanicLevel: "fatal",
+	zapcore.PanicLevel:  "fatal",
+	zapcore.FatalLevel:  "fatal",
+}
+
+func newEvent(ent zapcore.Entry, fields map[string]interface{}, cause error, options Options) *event {
+	id, _ := uuid.NewV4()
+
+	e := &event{
+		EventID:     strings.Replace(id.String(), "-", "", -1),
+		Timestamp:   ent.Time.UTC().Format(time.RFC3339Nano),
+		Level:       levels[ent.Level],
+		Logger:      ent.LoggerName,
+		Platform:    "go",
+		Message:     &message{Formatted: ent.Message},
+		Environment: options.Environment,
+		Release:     options.Release,
+		ServerName:  options.ServerName,
+		Tags:        map[string]string{},
+		Extra:       map[string]interface{}{},
+	}
+
+	if ent.Caller.Defined {
+		e.Culprit = ent.Caller.TrimmedPath()
+	}
+
+	for k, v := range fields {
+		e.Extra[k] = v
+	}
+	for _, key := range tagKeys {
+		if value, ok := e.Extra[key].(string); ok {
+			e.Tags[key] = value
+			delete(e.Extra, key)
+		}
+	}
+
+	if traceID, ok := e.Tags["traceId"]; ok {
+		e.Contexts = map[string]interface{}{
+			"trace": map[string]string{
+				"trace_id": traceID,
+				"span_id":  e.Tags["spanId"],
+			},
+		}
+	}
+
+	if "" != ent.Stack {
+		value := ent.Message
+		if err, ok := e.Extra["error"].(string); ok {
+			value = err
+		}
+		e.Exception = &exceptions{
+			Values: []exception{{
+				Type:       errorType(cause),
+				Value:      value,
+				Stacktrace: parseStack(ent.Stack),
+			}},
+		}
+	}
+
+	if nil != options.Fingerprint {
+		e.Fingerprint = options.Fingerprint(ent, fields)
+	}
+	if nil == options.Fingerprint {
+		e.Fingerprint = []string{ent.Message, e.Culprit}
+	}
+
+	return e
+}
+
+// errorType get type name of the innermost error of the chain, ex: `*fs.PathError`
+func errorType(err error) string {
+	if nil == err {
+		return "error"
+	}
+	for {
+		unwrapped := errors.Unwrap(err)
+		if nil == unwrapped {
+			return fmt.Sprintf("%T", err)
+		}
+		err = unwrapped
+	}
+}
+
+// parseStack parse zap stack trace, zap stack trace is ordered from the newest call, sentry frames is ordered from
+// the oldest call
+func parseStack(stack string) *stacktrace {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+
+	var frames []frame
+	for i := 0; i+1 < len(lines); i += 2 {
+		function := strings.TrimSpace(lines[i])
+		location := strings.TrimSpace(lines[i+1])
+
+		f := frame{
+			Function: function,
+			AbsPath:  location,
+			InApp:    !strings.HasPrefix(function, "runtime.") && !strings.HasPrefix(function, "net/http."),
+		}
+		if idx := strings.LastIndex(location, ":"); idx > 0 {
+			f.AbsPath = location[:idx]
+			f.Lineno, _ = strconv.Atoi(location[idx+1:])
+		}
+		start := strings.LastIndex(function, "/") + 1
+		if dot := strings.Index(function[start:], "."); dot > 0 {
+			f.Module = function[:start+dot]
+			f.Function = function[start+dot+1:]
+		}
+
+		frames = append([]frame{f}, frames...)
+	}
+
+	return &stacktrace{Frames: frames}
+}
+
+// envelope build sentry envelope, see https://develop.sentry.dev/sdk/envelopes/
+func (e *event) envelope(d *dsn) ([]byte, error) {
+	payload, err := json.Marshal(e)
+	if nil != err {
+		return nil, err
+	}
+
+	header, _ := json.Marshal(map[string]string{
+		"event_id": e.EventID,
+		"dsn":      d.raw,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	itemHeader, _ := json.Marshal(map[string]interface{}{
+		"type":   "event",
+		"length": len(payload),
+	})
+
+	buf := &bytes.Buffer{}
+	buf.Write(header)
+	buf.WriteByte('\n')
+	buf.Write(itemHeader)
+	buf.WriteByte('\n')
+	buf.Write(payload)
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
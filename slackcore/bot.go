@@ -0,0 +1,319 @@
+package slackcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"github.com/payfazz/fz-sentry/redact"
+	"github.com/slack-go/slack"
+	"go.uber.org/multierr"
+)
+
+const (
+	RESOLVED_TEXT = ":white_check_mark: resolved"
+
+	DEFAULT_UPLOAD_THRESHOLD = 1000
+	DEFAULT_THREAD_TTL       = 24 * time.Hour
+)
+
+type BotOptions struct {
+	Token        string
+	Channel      string
+	ResolveAfter time.Duration // post resolved reply when the alert doesn't occur for the duration, 0 to only resolve on resolved alert
+	ThreadTTL    time.Duration // forget alert thread when the alert doesn't occur for the duration, default to DEFAULT_THREAD_TTL
+	Renderer     Renderer      // default to BlockKitRenderer without links
+	Interactive  bool          // add acknowledge and silence buttons to parent message, see InteractionHandler
+
+	// fields larger than the threshold in bytes are uploaded as file snippet on the alert thread and linked from
+	// the alert, default to DEFAULT_UPLOAD_THRESHOLD, negative to disable
+	UploadThreshold int
+	Redactor        *redact.Redactor // redact uploaded fields, default to redact.Global()
+
+	APIURL     string // default to slack api url
+	HTTPClient *http.Client
+}
+
+type thread struct {
+	timestamp string
+	lastSeen  time.Time
+}
+
+type botSink struct {
+	options BotOptions
+	client  *slack.Client
+
+	errors alert.Errors
+	stop   chan struct{}
+	once   sync.Once
+
+	lock    sync.Mutex
+	threads map[string]*thread
+}
+
+// BotSink send alert using chat.postMessage, the first occurrence of an alert fingerprint is posted as parent message
+// and the next occurrences (including repeated summaries) are posted as its thread replies, the thread is forgotten
+// after ThreadTTL so the next occurrence is posted as new parent message, the sink implements io.Closer to stop the
+// thread expiry worker, it is closed by the slack core Close
+func BotSink(options BotOptions) alert.Sink {
+	if nil == options.Renderer {
+		options.Renderer = BlockKitRenderer(BlockKitOptions{})
+	}
+	if nil == options.HTTPClient {
+		options.HTTPClient = &http.Client{Timeout: DEFAULT_HTTP_TIMEOUT}
+	}
+	if 0 == options.UploadThreshold {
+		options.UploadThreshold = DEFAULT_UPLOAD_THRESHOLD
+	}
+	if 0 == options.ThreadTTL {
+		options.ThreadTTL = DEFAULT_THREAD_TTL
+	}
+
+	slackOptions := []slack.Option{slack.OptionHTTPClient(retryAfterClient(options.HTTPClient))}
+	if "" != options.APIURL {
+		slackOptions = append(slackOptions, slack.OptionAPIURL(options.APIURL))
+	}
+
+	s := &botSink{
+		options: options,
+		client:  slack.New(options.Token, slackOptions...),
+		threads: map[string]*thread{},
+		stop:    make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// Send post the alert, errors of background resolve are returned after successful delivery so they are written to
+// zap ErrorOutput, they are wrapped with %v so the delivery is not retried
+func (s *botSink) Send(a alert.Alert) error {
+	if err := s.send(a); nil != err {
+		return err
+	}
+	if err := s.errors.Take(); nil != err {
+		return fmt.Errorf("resolve: %v", err)
+	}
+	return nil
+}
+
+func (s *botSink) send(a alert.Alert) error {
+	s.lock.Lock()
+	t, ok := s.threads[a.Fingerprint]
+	if ok && a.Resolved {
+		delete(s.threads, a.Fingerprint)
+	}
+	if ok && !a.Resolved {
+		t.lastSeen = time.Now()
+	}
+	s.lock.Unlock()
+
+	if a.Resolved {
+		if !ok {
+			return nil
+		}
+		return s.resolve(t)
+	}
+
+	fields, uploads := s.splitFields(a.Fields)
+
+	msg := s.options.Renderer(a.Entry, fields)
+	if s.options.Interactive && !ok {
+		msg = withActions(msg, a.Fingerprint)
+	}
+
+	msgOptions := messageOptions(msg)
+	if ok {
+		channel, timestamp, err := s.client.PostMessage(s.options.Channel, append(msgOptions, slack.MsgOptionTS(t.timestamp))...)
+		if nil != err {
+			return err
+		}
+		return s.upload(a, fields, uploads, channel, timestamp, t.timestamp)
+	}
+
+	channel, timestamp, err := s.client.PostMessage(s.options.Channel, msgOptions...)
+	if nil != err {
+		return err
+	}
+
+	s.lock.Lock()
+	s.threads[a.Fingerprint] = &thread{timestamp: timestamp, lastSeen: time.Now()}
+	s.lock.Unlock()
+
+	return s.upload(a, fields, uploads, channel, timestamp, timestamp)
+}
+
+// splitFields replace fields larger than upload threshold with placeholder, the redacted values are returned as
+// uploads
+func (s *botSink) splitFields(fields map[string]interface{}) (map[string]interface{}, map[string]string) {
+	if s.options.UploadThreshold < 0 {
+		return fields, nil
+	}
+
+	var result map[string]interface{}
+	var uploads map[string]string
+	for key, value := range fields {
+		content := stringifyField(value)
+		if len(content) <= s.options.UploadThreshold {
+			continue
+		}
+
+		if nil == uploads {
+			result = make(map[string]interface{}, len(fields))
+			for k, v := range fields {
+				result[k] = v
+			}
+			uploads = map[string]string{}
+		}
+		uploads[key] = string(s.redactor().Redact([]byte(content)))
+		result[key] = fmt.Sprintf("_uploading %d bytes to thread_", len(content))
+	}
+
+	if nil == uploads {
+		return fields, nil
+	}
+	return result, uploads
+}
+
+func (s *botSink) redactor() *redact.Redactor {
+	if nil != s.options.Redactor {
+		return s.options.Redactor
+	}
+	return redact.Global()
+}
+
+// upload upload large fields to the alert thread and update the posted message with the file links, upload error
+// is returned without retry since the message is already posted
+func (s *botSink) upload(a alert.Alert, fields map[string]interface{}, uploads map[string]string, channel string, timestamp string, threadTimestamp string) error {
+	if 0 == len(uploads) {
+		return nil
+	}
+
+	var errs error
+	for _, key := range sortedKeys(fields) {
+		content, ok := uploads[key]
+		if !ok {
+			continue
+		}
+
+		filename, filetype := key+".txt", "text"
+		if json.Valid([]byte(content)) {
+			filename, filetype = key+".json", "json"
+		}
+
+		file, err := s.client.UploadFile(slack.FileUploadParameters{
+			Content:         content,
+			Filetype:        filetype,
+			Filename:        filename,
+			Title:           fmt.Sprintf("%s: %s", key, a.Entry.Message),
+			Channels:        []string{channel},
+			ThreadTimestamp: threadTimestamp,
+		})
+		if nil != err {
+			errs = multierr.Append(errs, fmt.Errorf("upload %s: %v", filename, err))
+			fields[key] = fmt.Sprintf("_failed to upload %d bytes_", len(content))
+			continue
+		}
+		fields[key] = fmt.Sprintf("<%s|%s>", file.Permalink, filename)
+	}
+
+	msg := s.options.Renderer(a.Entry, fields)
+	if s.options.Interactive && timestamp == threadTimestamp {
+		msg = withActions(msg, a.Fingerprint)
+	}
+	if _, _, _, err := s.client.UpdateMessage(channel, timestamp, messageOptions(msg)...); nil != err {
+		errs = multierr.Append(errs, fmt.Errorf("update message: %v", err))
+	}
+
+	return errs
+}
+
+func (s *botSink) resolve(t *thread) error {
+	_, _, err := s.client.PostMessage(s.options.Channel,
+		slack.MsgOptionText(RESOLVED_TEXT, false),
+		slack.MsgOptionTS(t.timestamp),
+	)
+	return err
+}
+
+// run resolve alert threads after ResolveAfter and forget them after ThreadTTL
+func (s *botSink) run() {
+	expiry := s.options.ThreadTTL
+	if s.options.ResolveAfter > 0 && s.options.ResolveAfter < expiry {
+		expiry = s.options.ResolveAfter
+	}
+	interval := expiry / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.expire(now)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Close stop the thread expiry worker and return pending resolve errors
+func (s *botSink) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	return s.errors.Take()
+}
+
+func (s *botSink) expire(now time.Time) {
+	var resolved []*thread
+
+	s.lock.Lock()
+	for fingerprint, t := range s.threads {
+		idle := now.Sub(t.lastSeen)
+		if s.options.ResolveAfter > 0 && idle >= s.options.ResolveAfter {
+			resolved = append(resolved, t)
+			delete(s.threads, fingerprint)
+			continue
+		}
+		if idle >= s.options.ThreadTTL {
+			delete(s.threads, fingerprint)
+		}
+	}
+	s.lock.Unlock()
+
+	for _, t := range resolved {
+		s.errors.Add(s.resolve(t))
+	}
+}
+
+func stringifyField(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	by, err := json.Marshal(value)
+	if nil != err {
+		return fmt.Sprint(value)
+	}
+	return string(by)
+}
+
+func messageOptions(msg *slack.WebhookMessage) []slack.MsgOption {
+	options := []slack.MsgOption{
+		slack.MsgOptionText(msg.Text, false),
+	}
+	if len(msg.Attachments) > 0 {
+		options = append(options, slack.MsgOptionAttachments(msg.Attachments...))
+	}
+	if nil != msg.Blocks {
+		options = append(options, slack.MsgOptionBlocks(msg.Blocks.BlockSet...))
+	}
+	return options
+}
@@ -0,0 +1,141 @@
+package slackcore
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"go.uber.org/zap/zapcore"
+)
+
+// slackStub is chat.postMessage stub, replies are failed when fail is set
+type slackStub struct {
+	*httptest.Server
+
+	lock    sync.Mutex
+	posts   int
+	replies int
+	fail    bool
+}
+
+func newSlackStub() *slackStub {
+	s := &slackStub{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if "" != r.FormValue("thread_ts") {
+			s.replies++
+			if s.fail {
+				_, _ = fmt.Fprint(w, `{"ok": false, "error": "channel_not_found"}`)
+				return
+			}
+		} else {
+			s.posts++
+		}
+		_, _ = fmt.Fprintf(w, `{"ok": true, "channel": "C1", "ts": "%d.000"}`, s.posts)
+	}))
+	return s
+}
+
+func (s *slackStub) count() (int, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.posts, s.replies
+}
+
+func testBotSink(stub *slackStub, options BotOptions) *botSink {
+	options.Token = "xoxb"
+	options.Channel = "C1"
+	options.APIURL = stub.URL + "/"
+	return BotSink(options).(*botSink)
+}
+
+func botAlert() alert.Alert {
+	return alert.Alert{Entry: zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom"}, Fingerprint: "abc"}
+}
+
+func TestBotSinkThreadTTL(t *testing.T) {
+	stub := newSlackStub()
+	defer stub.Close()
+
+	sink := testBotSink(stub, BotOptions{ThreadTTL: time.Minute})
+	if err := sink.Send(botAlert()); nil != err {
+		t.Fatal(err)
+	}
+	if err := sink.Send(botAlert()); nil != err {
+		t.Fatal(err)
+	}
+	if posts, replies := stub.count(); 1 != posts || 1 != replies {
+		t.Fatalf("next occurrence should be posted as reply, got %d posts %d replies", posts, replies)
+	}
+
+	sink.expire(time.Now().Add(time.Minute))
+	if 0 != len(sink.threads) {
+		t.Fatalf("expired thread should be forgotten, got %d threads", len(sink.threads))
+	}
+
+	if err := sink.Send(botAlert()); nil != err {
+		t.Fatal(err)
+	}
+	if posts, replies := stub.count(); 2 != posts || 1 != replies {
+		t.Errorf("occurrence after expiry should be posted as parent, got %d posts %d replies", posts, replies)
+	}
+}
+
+func TestBotSinkResolveError(t *testing.T) {
+	stub := newSlackStub()
+	defer stub.Close()
+
+	sink := testBotSink(stub, BotOptions{ResolveAfter: time.Minute})
+	if err := sink.Send(botAlert()); nil != err {
+		t.Fatal(err)
+	}
+
+	stub.lock.Lock()
+	stub.fail = true
+	stub.lock.Unlock()
+
+	sink.expire(time.Now().Add(time.Minute))
+	if _, replies := stub.count(); 1 != replies {
+		t.Fatalf("idle alert should be resolved, got %d replies", replies)
+	}
+
+	stub.lock.Lock()
+	stub.fail = false
+	stub.lock.Unlock()
+
+	if err := sink.Send(botAlert()); nil == err {
+		t.Error("resolve error should be returned from the next Send")
+	}
+	if err := sink.Send(botAlert()); nil != err {
+		t.Errorf("resolve error should only be returned once, got %v", err)
+	}
+}
+
+func TestBotSinkClose(t *testing.T) {
+	stub := newSlackStub()
+	defer stub.Close()
+
+	sink := testBotSink(stub, BotOptions{})
+	core := New(Options{
+		MinLevel: zapcore.ErrorLevel,
+		Routes:   []Route{{Sinks: []alert.Sink{sink}}, {Sinks: []alert.Sink{sink}}},
+	})
+	if err := core.(io.Closer).Close(); nil != err {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-sink.stop:
+	default:
+		t.Error("core Close should close the bot sink")
+	}
+}
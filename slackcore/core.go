@@ -2,89 +2,253 @@ package slackcore
 
 import (
 	"fmt"
+	"io"
+	"reflect"
+	"time"
 
-	"github.com/slack-go/slack"
-	"go.uber.org/zap"
+	"github.com/payfazz/fz-sentry/alert"
+	"go.uber.org/multierr"
 	"go.uber.org/zap/zapcore"
 )
 
 type slackCore struct {
-	zapcore.Core
-
-	hookURL  string
-	minLevel zapcore.Level
+	zapcore.LevelEnabler
 
+	hook   *hook
 	fields []zapcore.Field
 }
 
+// hook is slack delivery state shared by every core derived from the same core
+type hook struct {
+	options  Options
+	sender   *sender
+	dedup    *deduplicator
+	limiter  *limiter
+	digest   *digest
+	silences *alert.SilenceCounter
+}
+
 // NewWrapper ...
 func NewWrapper(hookURL string, minLevel zapcore.Level) func(zapcore.Core) zapcore.Core {
+	return NewWrapperWithOptions(Options{
+		HookURL:  hookURL,
+		MinLevel: minLevel,
+	})
+}
+
+// NewWrapperWithOptions create zap core wrapper that tee the wrapped core with slack core, see New
+func NewWrapperWithOptions(options Options) func(zapcore.Core) zapcore.Core {
+	sc := New(options)
 	return func(c zapcore.Core) zapcore.Core {
-		return &slackCore{
-			Core:     c,
-			hookURL:  hookURL,
-			minLevel: minLevel,
-		}
+		return zapcore.NewTee(c, sc)
+	}
+}
+
+// New create standalone slack core that alert entries at or above Options.MinLevel, messages are delivered
+// asynchronously by background worker, Sync wait for queued messages and delivery errors are returned from the
+// next Write or Sync so zap write them to its ErrorOutput, the core implements io.Closer to stop the background workers
+func New(options Options) zapcore.Core {
+	options = options.withDefaults()
+	return &slackCore{
+		LevelEnabler: options.MinLevel,
+		hook:         newHook(options),
+	}
+}
+
+func newHook(options Options) *hook {
+	h := &hook{
+		options: options,
+		sender:  newSender(options),
+		limiter: &limiter{limit: options.MaxMessagesPerMinute},
+	}
+	h.dedup = newDeduplicator(options.SuppressionWindow, h.summary, h.resolve, h.sender.stop)
+	if nil != options.Digest {
+		h.digest = newDigest(*options.Digest, h.send, h.sender.report, h.sender.stop)
+	}
+	if nil != options.Silences {
+		h.silences = options.Silences.NewCounter()
+		go h.runSilenceReport()
+	}
+	return h
+}
+
+func (c *slackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slackCore{
+		LevelEnabler: c.LevelEnabler,
+		hook:         c.hook,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
 	}
 }
 
+func (c *slackCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+	return ce
+}
+
 func (c *slackCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
-	// Join core fields with entry fields
-	fields = append(
-		append(
-			make([]zapcore.Field, 0, len(c.fields)+len(fields)),
-			c.fields...,
-		),
-		fields...,
-	)
-
-	if e.Level >= c.minLevel {
-		enc := zapcore.NewMapObjectEncoder()
-
-		for _, field := range fields {
-			field.AddTo(enc)
-		}
+	// entry may be written directly by wrapping core without Check, ex: request log buffer
+	if !c.Enabled(e.Level) {
+		return nil
+	}
 
-		attachment := slack.Attachment{
-			Color:    levelColor[e.Level],
-			Fallback: e.Message,
-		}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
 
-		attachment.Text = e.Message + "\n"
-		for k, v := range enc.Fields {
-			attachment.Text += fmt.Sprintf("*%s*\n%v\n", k, v)
-		}
+	c.hook.alert(e, enc.Fields)
 
-		msg := slack.WebhookMessage{
-			Attachments: []slack.Attachment{attachment},
-		}
+	// process may exit or panic right after fatal and panic entries, wait for the delivery like zap ioCore
+	if e.Level > zapcore.ErrorLevel {
+		return multierr.Append(c.hook.sender.flush(), c.hook.sender.errors.Take())
+	}
+	return c.hook.sender.errors.Take()
+}
+
+// Sync wait for queued slack messages and return delivery errors
+func (c *slackCore) Sync() error {
+	return multierr.Append(c.hook.sender.flush(), c.hook.sender.errors.Take())
+}
 
-		err := slack.PostWebhook(c.hookURL, &msg)
+// Close send pending in memory digest, wait for queued slack messages, stop the background workers and close sinks
+// implementing io.Closer, ex: BotSink, alerts written after Close are dropped
+func (c *slackCore) Close() error {
+	if nil != c.hook.digest {
+		c.hook.digest.close()
+	}
+	err := multierr.Append(c.hook.sender.close(), c.hook.sender.errors.Take())
+	for _, closer := range c.hook.closers() {
+		err = multierr.Append(err, closer.Close())
+	}
+	return err
+}
 
-		if err != nil {
-			fields = append(fields, zap.Error(err), zap.String("slack_error", "send event to slack error"))
+// closers get configured sinks implementing io.Closer, a sink used by multiple routes is only returned once
+func (h *hook) closers() []io.Closer {
+	var closers []io.Closer
+	seen := map[io.Closer]bool{}
+	add := func(sinks []alert.Sink) {
+		for _, sink := range sinks {
+			closer, ok := sink.(io.Closer)
+			if !ok {
+				continue
+			}
+			if reflect.TypeOf(closer).Comparable() {
+				if seen[closer] {
+					continue
+				}
+				seen[closer] = true
+			}
+			closers = append(closers, closer)
 		}
 	}
 
-	err := c.Core.Write(e, fields)
+	add(h.options.Sinks)
+	for _, r := range h.options.Routes {
+		add(r.Sinks)
+	}
+	return closers
+}
 
-	return err
+func (h *hook) alert(e zapcore.Entry, fields map[string]interface{}) {
+	a := alert.Alert{
+		Entry:       e,
+		Fields:      fields,
+		Fingerprint: fingerprint(e, fields, h.options.FingerprintFields),
+	}
+	if h.silences.Silenced(a, time.Now()) {
+		return
+	}
+	if nil != h.digest {
+		h.digest.add(a)
+		return
+	}
+	allow := func() bool {
+		return h.limiter.allow(time.Now())
+	}
+	if !h.dedup.observe(a, e.Time, allow) {
+		return
+	}
+
+	h.send(a)
 }
 
-func (c *slackCore) With(fields []zapcore.Field) zapcore.Core {
-	return &slackCore{
-		Core:     c.Core.With(nil),
-		hookURL:  c.hookURL,
-		minLevel: c.minLevel,
-		fields:   append(c.fields, fields...),
+func (h *hook) summary(a alert.Alert, count int) {
+	if !h.limiter.allow(time.Now()) {
+		return
+	}
+
+	a.Entry.Message = fmt.Sprintf("repeated %d times in last %s: %s", count, formatWindow(h.options.SuppressionWindow), a.Entry.Message)
+	a.Entry.Time = time.Now()
+	a.Entry.Stack = ""
+	h.send(a)
+}
+
+func (h *hook) resolve(a alert.Alert) {
+	if !h.options.SendResolved {
+		return
 	}
+
+	a.Resolved = true
+	a.Entry.Time = time.Now()
+	h.send(a)
 }
 
-func (c *slackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
-	if c.Enabled(ent.Level) {
-		return ce.AddCore(ent, c)
+// send deliver alert to every routed sink
+func (h *hook) send(a alert.Alert) {
+	for _, sink := range h.route(a) {
+		sink := sink
+		h.sender.enqueue(func() error {
+			return sink.Send(a)
+		})
+	}
+}
+
+// runSilenceReport send suppressed alert count of ended silences
+func (h *hook) runSilenceReport() {
+	ticker := time.NewTicker(alert.DEFAULT_SILENCE_REFRESH)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, report := range h.silences.Ended(now) {
+				h.send(silenceAlert(report, now))
+			}
+		case <-h.sender.stop:
+			return
+		}
+	}
+}
+
+func silenceAlert(report alert.SilenceReport, now time.Time) alert.Alert {
+	fields := map[string]interface{}{
+		"silenceId":  report.Silence.ID,
+		"suppressed": report.Suppressed,
+		"startsAt":   report.Silence.StartsAt.Format(time.RFC3339),
+		"endsAt":     report.Silence.EndsAt.Format(time.RFC3339),
+	}
+	if "" != report.Silence.CreatedBy {
+		fields["createdBy"] = report.Silence.CreatedBy
+	}
+	if "" != report.Silence.Comment {
+		fields["comment"] = report.Silence.Comment
+	}
+
+	return alert.Alert{
+		Entry: zapcore.Entry{
+			Level:   zapcore.InfoLevel,
+			Time:    now,
+			Message: fmt.Sprintf("silence ended, %d alerts suppressed", report.Suppressed),
+		},
+		Fields:      fields,
+		Fingerprint: "silence:" + report.Silence.ID,
 	}
-	return ce
 }
 
 var levelColor = map[zapcore.Level]string{
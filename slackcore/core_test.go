@@ -0,0 +1,156 @@
+package slackcore
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"github.com/payfazz/fz-sentry/alert/alerttest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCore(t *testing.T) {
+	alerttest.TestCore(t, func(t *testing.T, minLevel zapcore.Level) (zapcore.Core, func() []alert.Alert) {
+		recorder := &alerttest.Recorder{}
+		core := New(Options{MinLevel: minLevel, Sinks: []alert.Sink{recorder}})
+		return core, recorder.Alerts
+	})
+}
+
+func TestDeliveryError(t *testing.T) {
+	failing := alert.SinkFunc(func(a alert.Alert) error {
+		return errors.New("unreachable")
+	})
+	core := New(Options{MinLevel: zapcore.ErrorLevel, Sinks: []alert.Sink{failing}})
+
+	if err := core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom"}, nil); nil != err {
+		t.Errorf("Write should not wait for delivery, got %v", err)
+	}
+	if err := core.Sync(); nil == err {
+		t.Error("Sync should return delivery error")
+	}
+	if err := core.Sync(); nil != err {
+		t.Errorf("delivery error should only be returned once, got %v", err)
+	}
+}
+
+func TestDeliveryErrorOutput(t *testing.T) {
+	failing := alert.SinkFunc(func(a alert.Alert) error {
+		return errors.New("unreachable")
+	})
+	core := New(Options{MinLevel: zapcore.ErrorLevel, Sinks: []alert.Sink{failing}})
+	output := &recordSyncer{}
+
+	logger := zap.New(core, zap.ErrorOutput(output))
+	logger.Error("first")
+	_ = core.(*slackCore).hook.sender.flush()
+	logger.Error("second")
+
+	if 0 == len(output.written) {
+		t.Error("delivery error should be written to zap ErrorOutput")
+	}
+}
+
+type recordSyncer struct {
+	written []string
+}
+
+func (s *recordSyncer) Write(p []byte) (int, error) {
+	s.written = append(s.written, string(p))
+	return len(p), nil
+}
+
+func (s *recordSyncer) Sync() error {
+	return nil
+}
+
+func TestSyncFlushTimeout(t *testing.T) {
+	release := make(chan struct{})
+	blocking := alert.SinkFunc(func(a alert.Alert) error {
+		<-release
+		return nil
+	})
+	core := New(Options{MinLevel: zapcore.ErrorLevel, Sinks: []alert.Sink{blocking}, FlushTimeout: 10 * time.Millisecond})
+
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom"}, nil)
+	if err := core.Sync(); nil == err {
+		t.Error("Sync should return flush timeout error")
+	}
+
+	close(release)
+	if err := core.(io.Closer).Close(); nil != err {
+		t.Errorf("Close should deliver queued messages, got %v", err)
+	}
+
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "after close"}, nil)
+	if err := core.Sync(); nil != err {
+		t.Errorf("alert written after Close should be dropped, got %v", err)
+	}
+}
+
+func TestRateLimitedWithoutRetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if 1 == atomic.AddInt32(&attempts, 1) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	core := New(Options{HookURL: server.URL, MinLevel: zapcore.ErrorLevel, MinBackoff: time.Millisecond})
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom"}, nil)
+	if err := core.Sync(); nil != err {
+		t.Fatal(err)
+	}
+	if 2 != atomic.LoadInt32(&attempts) {
+		t.Errorf("rate limited message should be retried, got %d attempts", attempts)
+	}
+}
+
+func TestWriteFlushFatal(t *testing.T) {
+	recorder := &alerttest.Recorder{}
+	delayed := alert.SinkFunc(func(a alert.Alert) error {
+		time.Sleep(50 * time.Millisecond)
+		return recorder.Send(a)
+	})
+	core := New(Options{MinLevel: zapcore.ErrorLevel, Sinks: []alert.Sink{delayed}})
+
+	if err := core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "error"}, nil); nil != err {
+		t.Fatal(err)
+	}
+	if err := core.Write(zapcore.Entry{Level: zapcore.FatalLevel, Message: "fatal"}, nil); nil != err {
+		t.Fatal(err)
+	}
+	if 2 != len(recorder.Alerts()) {
+		t.Errorf("fatal entry should wait for queued alerts, got %d alerts", len(recorder.Alerts()))
+	}
+}
+
+func TestCloseStopDeduplicator(t *testing.T) {
+	recorder := &alerttest.Recorder{}
+	core := New(Options{MinLevel: zapcore.ErrorLevel, Sinks: []alert.Sink{recorder}, SuppressionWindow: 100 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom", Time: time.Now()}, nil)
+	}
+	if err := core.(io.Closer).Close(); nil != err {
+		t.Fatal(err)
+	}
+
+	dropped := DroppedMessages()
+	time.Sleep(1500 * time.Millisecond)
+	if dropped != DroppedMessages() {
+		t.Error("suppressed summary should not be enqueued after Close")
+	}
+	if 1 != len(recorder.Alerts()) {
+		t.Errorf("expected only the first alert, got %d", len(recorder.Alerts()))
+	}
+}
@@ -0,0 +1,131 @@
+package slackcore
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+)
+
+type occurrence struct {
+	alert       alert.Alert
+	windowStart time.Time
+	count       int
+}
+
+// deduplicator suppress the same alert within suppression window, the suppressed count is reported periodically
+// through onSummary, and onResolve is called when the alert doesn't occur for a whole window
+type deduplicator struct {
+	window    time.Duration
+	onSummary func(a alert.Alert, count int)
+	onResolve func(a alert.Alert)
+	stop      <-chan struct{}
+
+	lock        sync.Mutex
+	occurrences map[string]*occurrence
+}
+
+func newDeduplicator(window time.Duration, onSummary func(a alert.Alert, count int), onResolve func(a alert.Alert), stop <-chan struct{}) *deduplicator {
+	d := &deduplicator{
+		window:      window,
+		onSummary:   onSummary,
+		onResolve:   onResolve,
+		stop:        stop,
+		occurrences: map[string]*occurrence{},
+	}
+
+	if window > 0 {
+		go d.run()
+	}
+
+	return d
+}
+
+// observe record alert occurrence and return true if it is the first occurrence within the window and allowed to
+// be sent, the first occurrence is not recorded when it is not allowed so the next occurrence is sent instead of
+// summarized
+func (d *deduplicator) observe(a alert.Alert, now time.Time, allow func() bool) bool {
+	if d.window <= 0 {
+		return allow()
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if o, ok := d.occurrences[a.Fingerprint]; ok {
+		o.count++
+		return false
+	}
+	if !allow() {
+		return false
+	}
+
+	d.occurrences[a.Fingerprint] = &occurrence{
+		alert:       a,
+		windowStart: now,
+	}
+
+	return true
+}
+
+func (d *deduplicator) run() {
+	interval := d.window / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			d.summarize(now)
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// summarize report suppressed occurrences of expired windows, an alert that still occurs start a new window so
+// it keeps being suppressed and summarized
+func (d *deduplicator) summarize(now time.Time) {
+	var summaries []*occurrence
+	var resolved []alert.Alert
+
+	d.lock.Lock()
+	for key, o := range d.occurrences {
+		if now.Sub(o.windowStart) < d.window {
+			continue
+		}
+		if 0 == o.count {
+			delete(d.occurrences, key)
+			resolved = append(resolved, o.alert)
+			continue
+		}
+		summaries = append(summaries, &occurrence{alert: o.alert, count: o.count})
+		o.windowStart = now
+		o.count = 0
+	}
+	d.lock.Unlock()
+
+	for _, o := range summaries {
+		d.onSummary(o.alert, o.count)
+	}
+	for _, a := range resolved {
+		d.onResolve(a)
+	}
+}
+
+// formatWindow format window without zero unit suffix, ex: `5m` instead of `5m0s`
+func formatWindow(window time.Duration) string {
+	s := window.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
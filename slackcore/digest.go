@@ -0,0 +1,231 @@
+package slackcore
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	DEFAULT_DIGEST_INTERVAL   = time.Hour
+	DEFAULT_DIGEST_MAX_GROUPS = 20
+	DIGEST_FLUSH_DELAY        = 5 * time.Second
+)
+
+type DigestOptions struct {
+	Interval  time.Duration // aggregation window, ex: time.Hour; 24 * time.Hour, default to DEFAULT_DIGEST_INTERVAL
+	Store     DigestStore   // default to in memory store, use NewRedisDigestStore to share the digest between replicas
+	MaxGroups int           // max groups in the digest message ordered by count, default to DEFAULT_DIGEST_MAX_GROUPS
+}
+
+// DigestGroup is aggregated alerts with the same fingerprint
+type DigestGroup struct {
+	Fingerprint     string
+	Level           zapcore.Level
+	Message         string
+	Caller          string
+	Count           int64
+	FirstSeen       time.Time
+	LastSeen        time.Time
+	SampleRequestID string
+}
+
+// DigestStore keep digest groups per window, window is the start time of aggregation window
+type DigestStore interface {
+	Add(window time.Time, group DigestGroup) error
+	// Flush get and remove groups of the window, returned groups must be empty when the window is already flushed
+	Flush(window time.Time) ([]DigestGroup, error)
+}
+
+type memoryDigestStore struct {
+	lock    sync.Mutex
+	windows map[time.Time]map[string]*DigestGroup
+}
+
+// NewMemoryDigestStore create in process digest store
+func NewMemoryDigestStore() DigestStore {
+	return &memoryDigestStore{
+		windows: map[time.Time]map[string]*DigestGroup{},
+	}
+}
+
+func (s *memoryDigestStore) Add(window time.Time, group DigestGroup) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	groups, ok := s.windows[window]
+	if !ok {
+		groups = map[string]*DigestGroup{}
+		s.windows[window] = groups
+	}
+
+	existing, ok := groups[group.Fingerprint]
+	if !ok {
+		groups[group.Fingerprint] = &group
+		return nil
+	}
+
+	existing.Count += group.Count
+	if group.FirstSeen.Before(existing.FirstSeen) {
+		existing.FirstSeen = group.FirstSeen
+	}
+	if group.LastSeen.After(existing.LastSeen) {
+		existing.LastSeen = group.LastSeen
+	}
+	if "" == existing.SampleRequestID {
+		existing.SampleRequestID = group.SampleRequestID
+	}
+
+	return nil
+}
+
+func (s *memoryDigestStore) Flush(window time.Time) ([]DigestGroup, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var groups []DigestGroup
+	for w, windowGroups := range s.windows {
+		if w.After(window) {
+			continue
+		}
+		if w.Equal(window) {
+			for _, group := range windowGroups {
+				groups = append(groups, *group)
+			}
+		}
+		delete(s.windows, w)
+	}
+
+	return groups, nil
+}
+
+// digest aggregate alerts and send one summary alert every interval
+type digest struct {
+	options DigestOptions
+	send    func(a alert.Alert)
+	report  func(err error)
+	stop    <-chan struct{}
+}
+
+func newDigest(options DigestOptions, send func(a alert.Alert), report func(err error), stop <-chan struct{}) *digest {
+	if options.Interval <= 0 {
+		options.Interval = DEFAULT_DIGEST_INTERVAL
+	}
+	if nil == options.Store {
+		options.Store = NewMemoryDigestStore()
+	}
+	if options.MaxGroups <= 0 {
+		options.MaxGroups = DEFAULT_DIGEST_MAX_GROUPS
+	}
+
+	d := &digest{
+		options: options,
+		send:    send,
+		report:  report,
+		stop:    stop,
+	}
+	go d.run()
+
+	return d
+}
+
+func (d *digest) add(a alert.Alert) {
+	group := DigestGroup{
+		Fingerprint: a.Fingerprint,
+		Level:       a.Entry.Level,
+		Message:     a.Entry.Message,
+		Count:       1,
+		FirstSeen:   a.Entry.Time,
+		LastSeen:    a.Entry.Time,
+	}
+	if a.Entry.Caller.Defined {
+		group.Caller = a.Entry.Caller.TrimmedPath()
+	}
+	if requestId, ok := a.Fields["requestId"]; ok {
+		group.SampleRequestID = fmt.Sprint(requestId)
+	}
+
+	if err := d.options.Store.Add(a.Entry.Time.Truncate(d.options.Interval), group); nil != err {
+		d.report(err)
+	}
+}
+
+func (d *digest) run() {
+	for {
+		next := time.Now().Truncate(d.options.Interval).Add(d.options.Interval)
+		timer := time.NewTimer(time.Until(next) + DIGEST_FLUSH_DELAY)
+		select {
+		case <-timer.C:
+			d.flush(next.Add(-d.options.Interval))
+		case <-d.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// close flush the pending digest of the previous and current window of in memory store, so the aggregated alerts
+// are not lost when the process stops before the window ends, shared store is left for the other replicas
+func (d *digest) close() {
+	if _, ok := d.options.Store.(*memoryDigestStore); !ok {
+		return
+	}
+
+	window := time.Now().Truncate(d.options.Interval)
+	d.flush(window.Add(-d.options.Interval))
+	d.flush(window)
+}
+
+func (d *digest) flush(window time.Time) {
+	groups, err := d.options.Store.Flush(window)
+	if nil != err {
+		d.report(err)
+		return
+	}
+	if 0 == len(groups) {
+		return
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+
+	var total int64
+	level := zapcore.DebugLevel
+	for _, group := range groups {
+		total += group.Count
+		if group.Level > level {
+			level = group.Level
+		}
+	}
+
+	fields := map[string]interface{}{}
+	for i, group := range groups {
+		if i >= d.options.MaxGroups {
+			fields["others"] = fmt.Sprintf("%d more groups", len(groups)-i)
+			break
+		}
+		key := fmt.Sprintf("%02d. [%s] %s", i+1, group.Level.CapitalString(), group.Message)
+		fields[key] = fmt.Sprintf("count: %d\nfirst seen: %s\nlast seen: %s\ncaller: %s\nsample requestId: %s",
+			group.Count,
+			group.FirstSeen.Format(time.RFC3339),
+			group.LastSeen.Format(time.RFC3339),
+			group.Caller,
+			group.SampleRequestID,
+		)
+	}
+
+	d.send(alert.Alert{
+		Entry: zapcore.Entry{
+			Level:   level,
+			Time:    time.Now(),
+			Message: fmt.Sprintf("digest: %d alerts in %d groups in last %s", total, len(groups), formatWindow(d.options.Interval)),
+		},
+		Fields:      fields,
+		Fingerprint: fmt.Sprintf("digest-%d", window.Unix()),
+	})
+}
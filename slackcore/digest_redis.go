@@ -0,0 +1,137 @@
+package slackcore
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	DEFAULT_DIGEST_REDIS_PREFIX = "fz-sentry:digest"
+	DIGEST_REDIS_TTL            = 48 * time.Hour
+)
+
+// addDigestScript merge group into the window hash, group attributes are stored as `<fingerprint>:<attribute>`
+// fields, FirstSeen is lowered and LastSeen is raised so out of order adds from replicas are merged correctly, the
+// unix nano timestamps are compared as decimal string to keep the precision
+var addDigestScript = redis.NewScript(`
+local function before(a, b)
+	return #a < #b or (#a == #b and a < b)
+end
+
+local prefix = ARGV[1] .. ':'
+redis.call('HSETNX', KEYS[1], prefix .. 'level', ARGV[2])
+redis.call('HSETNX', KEYS[1], prefix .. 'message', ARGV[3])
+redis.call('HSETNX', KEYS[1], prefix .. 'caller', ARGV[4])
+local firstSeen = redis.call('HGET', KEYS[1], prefix .. 'firstSeen')
+if not firstSeen or before(ARGV[5], firstSeen) then
+	redis.call('HSET', KEYS[1], prefix .. 'firstSeen', ARGV[5])
+end
+local lastSeen = redis.call('HGET', KEYS[1], prefix .. 'lastSeen')
+if not lastSeen or before(lastSeen, ARGV[6]) then
+	redis.call('HSET', KEYS[1], prefix .. 'lastSeen', ARGV[6])
+end
+redis.call('HINCRBY', KEYS[1], prefix .. 'count', ARGV[7])
+if '' ~= ARGV[8] then
+	redis.call('HSETNX', KEYS[1], prefix .. 'sampleRequestId', ARGV[8])
+end
+redis.call('EXPIRE', KEYS[1], ARGV[9])
+return 1
+`)
+
+type redisDigestStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// NewRedisDigestStore create digest store shared between replicas, each window is stored as one hash and flushed
+// atomically so its groups are flushed only once
+func NewRedisDigestStore(client redis.Cmdable, prefix string) DigestStore {
+	if "" == prefix {
+		prefix = DEFAULT_DIGEST_REDIS_PREFIX
+	}
+	return &redisDigestStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *redisDigestStore) windowKey(window time.Time) string {
+	return fmt.Sprintf("%s:%d", s.prefix, window.Unix())
+}
+
+func (s *redisDigestStore) Add(window time.Time, group DigestGroup) error {
+	return addDigestScript.Run(s.client,
+		[]string{s.windowKey(window)},
+		group.Fingerprint,
+		int(group.Level),
+		group.Message,
+		group.Caller,
+		group.FirstSeen.UnixNano(),
+		group.LastSeen.UnixNano(),
+		group.Count,
+		group.SampleRequestID,
+		int(DIGEST_REDIS_TTL/time.Second),
+	).Err()
+}
+
+func (s *redisDigestStore) Flush(window time.Time) ([]DigestGroup, error) {
+	key := s.windowKey(window)
+
+	var result *redis.StringStringMapCmd
+	_, err := s.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		result = pipe.HGetAll(key)
+		pipe.Del(key)
+		return nil
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return parseDigestGroups(result.Val()), nil
+}
+
+// parseDigestGroups parse `<fingerprint>:<attribute>` hash fields into groups ordered by fingerprint
+func parseDigestGroups(fields map[string]string) []DigestGroup {
+	values := map[string]map[string]string{}
+	for field, value := range fields {
+		i := strings.LastIndex(field, ":")
+		if i < 0 {
+			continue
+		}
+		fingerprint := field[:i]
+		if _, ok := values[fingerprint]; !ok {
+			values[fingerprint] = map[string]string{}
+		}
+		values[fingerprint][field[i+1:]] = value
+	}
+
+	groups := make([]DigestGroup, 0, len(values))
+	for fingerprint, group := range values {
+		level, _ := strconv.Atoi(group["level"])
+		count, _ := strconv.ParseInt(group["count"], 10, 64)
+		firstSeen, _ := strconv.ParseInt(group["firstSeen"], 10, 64)
+		lastSeen, _ := strconv.ParseInt(group["lastSeen"], 10, 64)
+
+		groups = append(groups, DigestGroup{
+			Fingerprint:     fingerprint,
+			Level:           zapcore.Level(level),
+			Message:         group["message"],
+			Caller:          group["caller"],
+			Count:           count,
+			FirstSeen:       time.Unix(0, firstSeen),
+			LastSeen:        time.Unix(0, lastSeen),
+			SampleRequestID: group["sampleRequestId"],
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Fingerprint < groups[j].Fingerprint
+	})
+
+	return groups
+}
@@ -0,0 +1,97 @@
+package slackcore
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"github.com/payfazz/fz-sentry/alert/alerttest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestMemoryDigestStoreAdd(t *testing.T) {
+	store := NewMemoryDigestStore()
+	window := time.Date(2021, 1, 2, 3, 0, 0, 0, time.UTC)
+	at := func(minute int) time.Time {
+		return window.Add(time.Duration(minute) * time.Minute)
+	}
+
+	for _, group := range []DigestGroup{
+		{Fingerprint: "abc", Count: 1, FirstSeen: at(20), LastSeen: at(20)},
+		{Fingerprint: "abc", Count: 2, FirstSeen: at(10), LastSeen: at(15)},
+		{Fingerprint: "abc", Count: 1, FirstSeen: at(30), LastSeen: at(30), SampleRequestID: "req-1"},
+	} {
+		if err := store.Add(window, group); nil != err {
+			t.Fatal(err)
+		}
+	}
+
+	groups, err := store.Flush(window)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 1 != len(groups) {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	group := groups[0]
+	if 4 != group.Count || !at(10).Equal(group.FirstSeen) || !at(30).Equal(group.LastSeen) || "req-1" != group.SampleRequestID {
+		t.Errorf("unexpected group: %+v", group)
+	}
+
+	if groups, _ := store.Flush(window); 0 != len(groups) {
+		t.Errorf("flushed window should be empty, got %v", groups)
+	}
+}
+
+func TestCloseFlushDigest(t *testing.T) {
+	recorder := &alerttest.Recorder{}
+	core := New(Options{
+		MinLevel: zapcore.ErrorLevel,
+		Sinks:    []alert.Sink{recorder},
+		Digest:   &DigestOptions{Interval: time.Hour},
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := core.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom", Time: time.Now()}, nil); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if 0 != len(recorder.Alerts()) {
+		t.Fatal("digest alerts should not be sent in real time")
+	}
+
+	if err := core.Sync(); nil != err {
+		t.Fatal(err)
+	}
+	if 0 != len(recorder.Alerts()) {
+		t.Fatal("Sync should not send partial digest")
+	}
+
+	if err := core.(io.Closer).Close(); nil != err {
+		t.Fatal(err)
+	}
+	alerts := recorder.Alerts()
+	if 1 != len(alerts) || "digest: 3 alerts in 1 groups in last 1h" != alerts[0].Entry.Message {
+		t.Errorf("Close should send pending digest, got %v", alerts)
+	}
+}
+
+func TestParseDigestGroups(t *testing.T) {
+	groups := parseDigestGroups(map[string]string{
+		"svc:abc:level":     "2",
+		"svc:abc:message":   "boom",
+		"svc:abc:count":     "3",
+		"svc:abc:firstSeen": "1609556645000000001",
+		"svc:abc:lastSeen":  "1609556646000000000",
+		"def:count":         "1",
+	})
+
+	if 2 != len(groups) || "def" != groups[0].Fingerprint || "svc:abc" != groups[1].Fingerprint {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	group := groups[1]
+	if zapcore.ErrorLevel != group.Level || "boom" != group.Message || 3 != group.Count || 1 != group.FirstSeen.Nanosecond() {
+		t.Errorf("unexpected group: %+v", group)
+	}
+}
@@ -0,0 +1,19 @@
+package slackcore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// fingerprint identify the same alert using entry message, caller and selected fields value
+func fingerprint(e zapcore.Entry, fields map[string]interface{}, keys []string) string {
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s\n%s\n", e.Message, e.Caller.TrimmedPath())
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(hash, "%s=%v\n", key, fields[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,197 @@
+package slackcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"github.com/payfazz/fz-sentry/httperror"
+	"github.com/payfazz/fz-sentry/loghttp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	ACTION_BLOCK_ID    = "fz_sentry_actions"
+	ACTION_ACKNOWLEDGE = "fz_sentry_acknowledge"
+	ACTION_SILENCE     = "fz_sentry_silence"
+
+	SILENCE_DURATION             = time.Hour
+	DEFAULT_ACKNOWLEDGE_DURATION = 30 * time.Minute
+
+	MAX_INTERACTION_BODY = 1 << 20
+)
+
+type InteractionOptions struct {
+	SigningSecret       string          // required, slack app signing secret
+	Silences            *alert.Silences // required, registry updated by the buttons, use the same registry as Options.Silences
+	AcknowledgeDuration time.Duration   // how long an acknowledged alert is silenced, default to DEFAULT_ACKNOWLEDGE_DURATION
+	HTTPClient          *http.Client
+	ErrorOutput         zapcore.WriteSyncer // errors of posting confirmation to response_url, default to stderr like zap
+}
+
+type interactionHandler struct {
+	options InteractionOptions
+}
+
+type interactionResponse struct {
+	ResponseType    string `json:"response_type"`
+	ReplaceOriginal bool   `json:"replace_original"`
+	Text            string `json:"text"`
+}
+
+// InteractionHandler create http handler for slack interactivity request url, it verify the request signature and
+// silence the alert fingerprint when "Acknowledge" or "Silence 1h" button is clicked, error is returned when signing
+// secret or silence registry is not given
+func InteractionHandler(options InteractionOptions) (http.Handler, error) {
+	if "" == options.SigningSecret {
+		return nil, errors.New("slack interaction: signing secret is required")
+	}
+	if nil == options.Silences {
+		return nil, errors.New("slack interaction: silence registry is required")
+	}
+	if options.AcknowledgeDuration <= 0 {
+		options.AcknowledgeDuration = DEFAULT_ACKNOWLEDGE_DURATION
+	}
+	if nil == options.HTTPClient {
+		options.HTTPClient = &http.Client{Timeout: DEFAULT_HTTP_TIMEOUT}
+	}
+	if nil == options.ErrorOutput {
+		options.ErrorOutput = zapcore.Lock(os.Stderr)
+	}
+	return &interactionHandler{
+		options: options,
+	}, nil
+}
+
+func (h *interactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if http.MethodPost != r.Method {
+		loghttp.ErrorWithRequest(w, r, httperror.MethodNotAllowed(fmt.Errorf("method %s is not allowed", r.Method)))
+		return
+	}
+
+	verifier, err := slack.NewSecretsVerifier(r.Header, h.options.SigningSecret)
+	if nil != err {
+		loghttp.ErrorWithRequest(w, r, httperror.Unauthorized(err))
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.TeeReader(io.LimitReader(r.Body, MAX_INTERACTION_BODY), &verifier))
+	if nil != err {
+		loghttp.ErrorWithRequest(w, r, httperror.BadRequest(err))
+		return
+	}
+	if err := verifier.Ensure(); nil != err {
+		loghttp.ErrorWithRequest(w, r, httperror.Unauthorized(errors.New("invalid slack signature")))
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if nil != err {
+		loghttp.ErrorWithRequest(w, r, httperror.BadRequest(err))
+		return
+	}
+
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(form.Get("payload")), &callback); nil != err {
+		loghttp.ErrorWithRequest(w, r, httperror.BadRequest(err))
+		return
+	}
+
+	var confirmations []string
+	for _, action := range callback.ActionCallback.BlockActions {
+		var duration time.Duration
+		var text string
+		switch action.ActionID {
+		case ACTION_ACKNOWLEDGE:
+			duration = h.options.AcknowledgeDuration
+			text = "acknowledged"
+		case ACTION_SILENCE:
+			duration = SILENCE_DURATION
+			text = "silenced"
+		default:
+			continue
+		}
+
+		now := time.Now()
+		_, err := h.options.Silences.Add(alert.Silence{
+			Fingerprint: action.Value,
+			StartsAt:    now,
+			EndsAt:      now.Add(duration),
+			CreatedBy:   callback.User.Name,
+			Comment:     fmt.Sprintf("%s from slack", text),
+		})
+		if nil != err {
+			loghttp.ErrorWithRequest(w, r, httperror.InternalServer(err))
+			return
+		}
+
+		confirmations = append(confirmations, fmt.Sprintf(":mute: %s by <@%s> for %s", text, callback.User.ID, formatWindow(duration)))
+	}
+
+	// slack require the interaction to be acknowledged within 3 seconds, confirmations are posted afterward
+	w.WriteHeader(http.StatusOK)
+
+	if "" != callback.ResponseURL && len(confirmations) > 0 {
+		go h.respond(callback.ResponseURL, confirmations)
+	}
+}
+
+// respond post confirmation messages to the interaction response url, errors are written to ErrorOutput
+func (h *interactionHandler) respond(responseURL string, texts []string) {
+	for _, text := range texts {
+		if err := h.post(responseURL, text); nil != err {
+			fmt.Fprintf(h.options.ErrorOutput, "%v slack interaction response error: %v\n", time.Now(), err)
+			_ = h.options.ErrorOutput.Sync()
+		}
+	}
+}
+
+func (h *interactionHandler) post(responseURL string, text string) error {
+	by, _ := json.Marshal(interactionResponse{
+		ResponseType:    "in_channel",
+		ReplaceOriginal: false,
+		Text:            text,
+	})
+	resp, err := h.options.HTTPClient.Post(responseURL, "application/json", bytes.NewReader(by))
+	if nil != err {
+		// url error contain the response url which is a credential to post to the channel
+		if urlError, ok := err.(*url.Error); ok {
+			return urlError.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%d %s: %s", resp.StatusCode, http.StatusText(resp.StatusCode), body)
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// withActions append acknowledge and silence buttons carrying the alert fingerprint to the message
+func withActions(msg *slack.WebhookMessage, fingerprint string) *slack.WebhookMessage {
+	result := *msg
+
+	var blocks []slack.Block
+	if nil != msg.Blocks {
+		blocks = append(blocks, msg.Blocks.BlockSet...)
+	}
+	blocks = append(blocks, slack.NewActionBlock(ACTION_BLOCK_ID,
+		slack.NewButtonBlockElement(ACTION_ACKNOWLEDGE, fingerprint, plainText("Acknowledge")).WithStyle(slack.StylePrimary),
+		slack.NewButtonBlockElement(ACTION_SILENCE, fingerprint, plainText("Silence 1h")).WithStyle(slack.StyleDanger),
+	))
+	result.Blocks = &slack.Blocks{BlockSet: blocks}
+
+	return &result
+}
@@ -0,0 +1,106 @@
+package slackcore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+)
+
+const testSigningSecret = "secret"
+
+// chanSyncer send every write to the channel
+type chanSyncer chan string
+
+func (s chanSyncer) Write(p []byte) (int, error) {
+	s <- string(p)
+	return len(p), nil
+}
+
+func (s chanSyncer) Sync() error {
+	return nil
+}
+
+func interactionRequest(responseURL string) *http.Request {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":         "block_actions",
+		"response_url": responseURL,
+		"user":         map[string]string{"id": "U1", "name": "oncall"},
+		"actions": []map[string]string{
+			{"action_id": ACTION_ACKNOWLEDGE, "block_id": ACTION_BLOCK_ID, "value": "fingerprint"},
+		},
+	})
+	body := url.Values{"payload": {string(payload)}}.Encode()
+
+	timestamp := fmt.Sprint(time.Now().Unix())
+	mac := hmac.New(sha256.New, []byte(testSigningSecret))
+	_, _ = fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+
+	r := httptest.NewRequest(http.MethodPost, "/slack/interaction", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestInteractionRespondAsync(t *testing.T) {
+	release := make(chan struct{})
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer responder.Close()
+	defer close(release)
+
+	output := make(chanSyncer, 1)
+	silences := alert.NewSilences(nil)
+	handler, err := InteractionHandler(InteractionOptions{
+		SigningSecret: testSigningSecret,
+		Silences:      silences,
+		ErrorOutput:   output,
+	})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, interactionRequest(responder.URL+"/hooks/secret"))
+		done <- w
+	}()
+
+	select {
+	case w := <-done:
+		if http.StatusOK != w.Code {
+			t.Fatalf("status should be 200, got %d: %s", w.Code, w.Body.String())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler should respond before posting to response url")
+	}
+	if !silences.Silenced(alert.Alert{Fingerprint: "fingerprint"}, time.Now()) {
+		t.Error("fingerprint should be silenced")
+	}
+
+	release <- struct{}{}
+	select {
+	case written := <-output:
+		if !strings.Contains(written, "404") {
+			t.Errorf("response url failure should be reported, got %q", written)
+		}
+		if strings.Contains(written, "/hooks/secret") {
+			t.Errorf("response url should not be reported, got %q", written)
+		}
+	case <-time.After(time.Second):
+		t.Error("response url failure should be reported")
+	}
+}
@@ -0,0 +1,44 @@
+package slackcore
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var rateLimitedMessages uint64
+
+// RateLimitedMessages get total messages dropped because MaxMessagesPerMinute is reached
+func RateLimitedMessages() uint64 {
+	return atomic.LoadUint64(&rateLimitedMessages)
+}
+
+// limiter limit messages sent per minute, 0 limit means unlimited
+type limiter struct {
+	limit int
+
+	lock        sync.Mutex
+	windowStart time.Time
+	count       int
+}
+
+func (l *limiter) allow(now time.Time) bool {
+	if l.limit <= 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if now.Sub(l.windowStart) >= time.Minute {
+		l.windowStart = now
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		atomic.AddUint64(&rateLimitedMessages, 1)
+		return false
+	}
+	l.count++
+
+	return true
+}
@@ -0,0 +1,71 @@
+package slackcore
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	DEFAULT_QUEUE_SIZE    = 1000
+	DEFAULT_MAX_RETRIES   = 3
+	DEFAULT_MIN_BACKOFF   = time.Second
+	DEFAULT_MAX_BACKOFF   = 30 * time.Second
+	DEFAULT_FLUSH_TIMEOUT = 5 * time.Second
+	DEFAULT_HTTP_TIMEOUT  = 10 * time.Second
+
+	DEFAULT_SUPPRESSION_WINDOW = 5 * time.Minute
+)
+
+type Options struct {
+	HookURL  string       // fallback hook url when no route is matched
+	Sinks    []alert.Sink // fallback non slack sinks when no route is matched, ex: alert.TeamsSink
+	MinLevel zapcore.Level
+	Routes   []Route // send alert to different hook urls and sinks based on the entry, see Route
+
+	QueueSize    int           // max queued messages, default to DEFAULT_QUEUE_SIZE
+	DropPolicy   DropPolicy    // which message dropped when the queue is full, default to DROP_NEWEST
+	MaxRetries   int           // max retries on rate limited and 5xx error, default to DEFAULT_MAX_RETRIES, negative to disable
+	MinBackoff   time.Duration // first retry delay, doubled on each retry, default to DEFAULT_MIN_BACKOFF
+	MaxBackoff   time.Duration // max retry delay, default to DEFAULT_MAX_BACKOFF
+	FlushTimeout time.Duration // max time Sync waits for queued messages, default to DEFAULT_FLUSH_TIMEOUT
+
+	SuppressionWindow    time.Duration  // suppress the same alert within the window and post repeated count summary, 0 to disable, ex: DEFAULT_SUPPRESSION_WINDOW
+	FingerprintFields    []string       // fields used with message and caller to identify the same alert
+	MaxMessagesPerMinute int            // max messages posted per minute, 0 means unlimited
+	SendResolved         bool           // send resolved alert when the alert doesn't occur for a whole suppression window
+	Digest               *DigestOptions // aggregate alerts and send them periodically as one digest instead of real time alert
+
+	Renderer    Renderer        // default to BlockKitRenderer without links
+	Interactive bool            // add acknowledge and silence buttons to webhook messages, see InteractionHandler
+	Silences    *alert.Silences // alerts matched by an active silence are not sent, suppressed count is sent when the silence ends
+
+	HTTPClient *http.Client
+}
+
+func (o Options) withDefaults() Options {
+	if o.QueueSize <= 0 {
+		o.QueueSize = DEFAULT_QUEUE_SIZE
+	}
+	if 0 == o.MaxRetries {
+		o.MaxRetries = DEFAULT_MAX_RETRIES
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DEFAULT_MIN_BACKOFF
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DEFAULT_MAX_BACKOFF
+	}
+	if o.FlushTimeout <= 0 {
+		o.FlushTimeout = DEFAULT_FLUSH_TIMEOUT
+	}
+	if nil == o.Renderer {
+		o.Renderer = BlockKitRenderer(BlockKitOptions{})
+	}
+	if nil == o.HTTPClient {
+		o.HTTPClient = &http.Client{Timeout: DEFAULT_HTTP_TIMEOUT}
+	}
+	return o
+}
@@ -0,0 +1,229 @@
+package slackcore
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	MAX_HEADER_LENGTH = 150
+	MAX_FIELD_LENGTH  = 2000
+	MAX_FIELDS        = 10
+	MAX_STACK_LENGTH  = 2500
+
+	MAX_BLOCKS         = 50   // slack message block limit, one block is reserved for interaction buttons
+	MAX_SECTION_LENGTH = 3000 // slack section text limit
+)
+
+// Renderer render log entry and its fields into slack message
+type Renderer func(e zapcore.Entry, fields map[string]interface{}) *slack.WebhookMessage
+
+// Link is a button linking to log search or tracing ui, `{traceId}` and `{requestId}` on URLTemplate will be
+// replaced with the alert value, ex: `https://tracing.example.com/trace/{traceId}`
+type Link struct {
+	Text        string
+	URLTemplate string
+}
+
+type BlockKitOptions struct {
+	Environment    string
+	Links          []Link
+	MaxStackLength int // default to MAX_STACK_LENGTH, clamped so the fenced stack fits in a section
+}
+
+// fields rendered on the summary section instead of the detail section
+var summaryKeys = map[string]bool{
+	"serviceName": true,
+	"env":         true,
+	"requestId":   true,
+	"traceId":     true,
+	"spanId":      true,
+}
+
+var levelEmoji = map[zapcore.Level]string{
+	zapcore.DebugLevel:  ":large_purple_circle:",
+	zapcore.InfoLevel:   ":large_green_circle:",
+	zapcore.WarnLevel:   ":large_yellow_circle:",
+	zapcore.ErrorLevel:  ":red_circle:",
+	zapcore.DPanicLevel: ":red_circle:",
+	zapcore.PanicLevel:  ":red_circle:",
+	zapcore.FatalLevel:  ":red_circle:",
+}
+
+// BlockKitRenderer render entry as block kit message with header, summary fields, detail fields, stack trace and
+// link buttons
+func BlockKitRenderer(options BlockKitOptions) Renderer {
+	if options.MaxStackLength <= 0 {
+		options.MaxStackLength = MAX_STACK_LENGTH
+	}
+	if options.MaxStackLength > MAX_SECTION_LENGTH-len("``````") {
+		options.MaxStackLength = MAX_SECTION_LENGTH - len("``````")
+	}
+
+	return func(e zapcore.Entry, fields map[string]interface{}) *slack.WebhookMessage {
+		env := options.Environment
+		if value, ok := fields["env"]; ok {
+			env = fmt.Sprint(value)
+		}
+
+		blocks := []slack.Block{
+			slack.NewHeaderBlock(plainText(truncate(fmt.Sprintf("%s %s", levelEmoji[e.Level], e.Message), MAX_HEADER_LENGTH))),
+		}
+
+		summary := []*slack.TextBlockObject{
+			field("service", fields["serviceName"]),
+			field("env", env),
+			field("level", e.Level.CapitalString()),
+			field("time", e.Time.Format(time.RFC3339)),
+		}
+		if e.Caller.Defined {
+			summary = append(summary, field("caller", e.Caller.TrimmedPath()))
+		}
+		for _, key := range []string{"requestId", "traceId"} {
+			if value, ok := fields[key]; ok {
+				summary = append(summary, field(key, value))
+			}
+		}
+		blocks = append(blocks, slack.NewSectionBlock(nil, summary, nil))
+
+		var buttons []slack.BlockElement
+		for i, link := range options.Links {
+			linkURL, ok := fillTemplate(link.URLTemplate, fields)
+			if !ok {
+				continue
+			}
+			button := slack.NewButtonBlockElement(fmt.Sprintf("link_%d", i), "", plainText(link.Text))
+			button.URL = linkURL
+			buttons = append(buttons, button)
+		}
+
+		// blocks after the details, including interaction buttons
+		reserved := 1
+		if "" != e.Stack {
+			reserved++
+		}
+		if len(buttons) > 0 {
+			reserved++
+		}
+
+		var details []string
+		for _, key := range sortedKeys(fields) {
+			if !summaryKeys[key] {
+				details = append(details, key)
+			}
+		}
+		for len(details) > 0 {
+			// collapse the rest when there is only room for one more detail block
+			if len(details) > MAX_FIELDS && len(blocks)+reserved+2 > MAX_BLOCKS {
+				blocks = append(blocks, slack.NewContextBlock("", markdownText(truncate(
+					fmt.Sprintf("%d more fields omitted: %s", len(details), strings.Join(details, ", ")),
+					MAX_SECTION_LENGTH,
+				))))
+				break
+			}
+
+			n := len(details)
+			if n > MAX_FIELDS {
+				n = MAX_FIELDS
+			}
+			section := make([]*slack.TextBlockObject, 0, n)
+			for _, key := range details[:n] {
+				section = append(section, field(key, fields[key]))
+			}
+			blocks = append(blocks, slack.NewSectionBlock(nil, section, nil))
+			details = details[n:]
+		}
+
+		if "" != e.Stack {
+			blocks = append(blocks, slack.NewSectionBlock(
+				markdownText(fmt.Sprintf("```%s```", truncate(e.Stack, options.MaxStackLength))), nil, nil,
+			))
+		}
+
+		if len(buttons) > 0 {
+			blocks = append(blocks, slack.NewActionBlock("", buttons...))
+		}
+
+		return &slack.WebhookMessage{
+			Text:   e.Message,
+			Blocks: &slack.Blocks{BlockSet: blocks},
+		}
+	}
+}
+
+// AttachmentRenderer render entry as legacy attachment with sorted fields
+func AttachmentRenderer() Renderer {
+	return func(e zapcore.Entry, fields map[string]interface{}) *slack.WebhookMessage {
+		attachment := slack.Attachment{
+			Color:    levelColor[e.Level],
+			Fallback: e.Message,
+		}
+
+		attachment.Text = e.Message + "\n"
+		for _, k := range sortedKeys(fields) {
+			attachment.Text += fmt.Sprintf("*%s*\n%v\n", k, fields[k])
+		}
+
+		return &slack.WebhookMessage{
+			Attachments: []slack.Attachment{attachment},
+		}
+	}
+}
+
+// fillTemplate fill link template, return false if the template need value that is not available
+func fillTemplate(template string, fields map[string]interface{}) (string, bool) {
+	for _, key := range []string{"traceId", "requestId"} {
+		placeholder := "{" + key + "}"
+		if !strings.Contains(template, placeholder) {
+			continue
+		}
+		value, ok := fields[key]
+		if !ok {
+			return "", false
+		}
+		template = strings.Replace(template, placeholder, url.QueryEscape(fmt.Sprint(value)), -1)
+	}
+	return template, true
+}
+
+func field(key string, value interface{}) *slack.TextBlockObject {
+	if nil == value || "" == value {
+		value = "-"
+	}
+	return markdownText(truncate(fmt.Sprintf("*%s*\n%v", key, value), MAX_FIELD_LENGTH))
+}
+
+func plainText(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.PlainTextType, text, true, false)
+}
+
+func markdownText(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - 3
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
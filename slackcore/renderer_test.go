@@ -0,0 +1,56 @@
+package slackcore
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBlockKitRendererBlockLimit(t *testing.T) {
+	fields := map[string]interface{}{}
+	for i := 0; i < 1000; i++ {
+		fields[fmt.Sprintf("field%04d", i)] = i
+	}
+	renderer := BlockKitRenderer(BlockKitOptions{Links: []Link{{Text: "logs", URLTemplate: "https://logs.example.com"}}})
+
+	msg := withActions(renderer(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom", Stack: "stack"}, fields), "abc")
+	blocks := msg.Blocks.BlockSet
+	if len(blocks) > MAX_BLOCKS {
+		t.Fatalf("blocks exceed limit: %d", len(blocks))
+	}
+
+	var omitted *slack.ContextBlock
+	for _, block := range blocks {
+		if context, ok := block.(*slack.ContextBlock); ok {
+			omitted = context
+		}
+	}
+	if nil == omitted {
+		t.Fatal("overflow fields should be collapsed into context block")
+	}
+	text := omitted.ContextElements.Elements[0].(*slack.TextBlockObject).Text
+	if !strings.HasPrefix(text, "560 more fields omitted: field0440") {
+		t.Errorf("unexpected omitted text: %.50s", text)
+	}
+	if utf8.RuneCountInString(text) > MAX_SECTION_LENGTH {
+		t.Errorf("omitted text exceed limit: %d", utf8.RuneCountInString(text))
+	}
+}
+
+func TestBlockKitRendererStackLength(t *testing.T) {
+	renderer := BlockKitRenderer(BlockKitOptions{MaxStackLength: 10000})
+
+	msg := renderer(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom", Stack: strings.Repeat("s", 10000)}, nil)
+	blocks := msg.Blocks.BlockSet
+	stack := blocks[len(blocks)-1].(*slack.SectionBlock).Text.Text
+	if len(stack) > MAX_SECTION_LENGTH {
+		t.Errorf("stack section exceed limit: %d", len(stack))
+	}
+	if !strings.HasSuffix(stack, "...```") {
+		t.Errorf("stack should be truncated inside the fence")
+	}
+}
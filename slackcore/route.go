@@ -0,0 +1,75 @@
+package slackcore
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/payfazz/fz-sentry/alert"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Route send matched alert to HookURL and Sinks, an alert is matched when every non empty matcher is matched
+// - MinLevel: minimum entry level
+// - Logger: pattern for logger name
+// - Message: pattern for entry message
+// - Fields: field value equality, ex: {"component": "payment"}
+// routes are evaluated in order and stop on the first matched route unless Continue is true,
+// Options.HookURL and Options.Sinks are used as fallback when no route is matched
+type Route struct {
+	HookURL  string
+	Sinks    []alert.Sink
+	MinLevel zapcore.Level
+	Logger   *regexp.Regexp
+	Message  *regexp.Regexp
+	Fields   map[string]string
+	Continue bool
+}
+
+func (r Route) match(e zapcore.Entry, fields map[string]interface{}) bool {
+	if e.Level < r.MinLevel {
+		return false
+	}
+	if nil != r.Logger && !r.Logger.MatchString(e.LoggerName) {
+		return false
+	}
+	if nil != r.Message && !r.Message.MatchString(e.Message) {
+		return false
+	}
+	for key, expected := range r.Fields {
+		value, ok := fields[key]
+		if !ok || fmt.Sprint(value) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+// route get sinks of matched routes, fallback to options hook url and sinks if no route is matched
+func (h *hook) route(a alert.Alert) []alert.Sink {
+	var sinks []alert.Sink
+	matched := false
+	for _, r := range h.options.Routes {
+		if !r.match(a.Entry, a.Fields) {
+			continue
+		}
+		matched = true
+		sinks = append(sinks, h.sinks(r.HookURL, r.Sinks)...)
+		if !r.Continue {
+			break
+		}
+	}
+
+	if !matched {
+		sinks = h.sinks(h.options.HookURL, h.options.Sinks)
+	}
+
+	return sinks
+}
+
+func (h *hook) sinks(hookURL string, sinks []alert.Sink) []alert.Sink {
+	if "" == hookURL {
+		return sinks
+	}
+	return append([]alert.Sink{webhookSink(hookURL, h.options.Renderer, h.options.HTTPClient, h.options.Interactive)}, sinks...)
+}
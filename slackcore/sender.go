@@ -0,0 +1,215 @@
+package slackcore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"github.com/slack-go/slack"
+)
+
+type DropPolicy int
+
+const (
+	DROP_NEWEST DropPolicy = iota // drop incoming message when the queue is full
+	DROP_OLDEST                   // drop the oldest queued message when the queue is full
+)
+
+var droppedMessages uint64
+
+// DroppedMessages get total messages dropped because the queue is full
+func DroppedMessages() uint64 {
+	return atomic.LoadUint64(&droppedMessages)
+}
+
+type httpStatusCode interface {
+	HTTPStatusCode() int
+}
+
+// sender deliver messages on background worker, so slow or unreachable slack will not block logging
+type sender struct {
+	options Options
+	queue   chan func() error
+	errors  alert.Errors
+	stop    chan struct{}
+
+	lock    sync.Mutex
+	pending int
+	idle    chan struct{} // closed when there is no pending message
+	closed  bool
+}
+
+func newSender(options Options) *sender {
+	s := &sender{
+		options: options,
+		queue:   make(chan func() error, options.QueueSize),
+		stop:    make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+func (s *sender) enqueue(delivery func() error) {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		atomic.AddUint64(&droppedMessages, 1)
+		return
+	}
+	if 0 == s.pending {
+		s.idle = make(chan struct{})
+	}
+	s.pending++
+	s.lock.Unlock()
+
+	select {
+	case s.queue <- delivery:
+		return
+	default:
+	}
+
+	if DROP_OLDEST == s.options.DropPolicy {
+		select {
+		case <-s.queue:
+			s.drop()
+		default:
+		}
+
+		select {
+		case s.queue <- delivery:
+			return
+		default:
+		}
+	}
+
+	s.drop()
+}
+
+func (s *sender) drop() {
+	atomic.AddUint64(&droppedMessages, 1)
+	s.done()
+}
+
+func (s *sender) done() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.pending--
+	if 0 == s.pending {
+		close(s.idle)
+	}
+}
+
+func (s *sender) run() {
+	for {
+		select {
+		case delivery := <-s.queue:
+			s.deliver(delivery)
+			s.done()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *sender) deliver(delivery func() error) {
+	for attempt := 0; ; attempt++ {
+		err := delivery()
+		if nil == err {
+			return
+		}
+
+		wait, retryable := s.retryDelay(err, attempt)
+		if !retryable || attempt >= s.options.MaxRetries {
+			s.report(err)
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.stop:
+			timer.Stop()
+			s.report(err)
+			return
+		}
+	}
+}
+
+// retryDelay get exponential backoff delay for rate limited and 5xx error, slack Retry-After is used when it's
+// longer than the backoff delay
+func (s *sender) retryDelay(err error, attempt int) (time.Duration, bool) {
+	backoff := s.options.MinBackoff << uint(attempt)
+	if backoff <= 0 || backoff > s.options.MaxBackoff {
+		backoff = s.options.MaxBackoff
+	}
+
+	var rateLimited *slack.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		if rateLimited.RetryAfter > backoff {
+			return rateLimited.RetryAfter, true
+		}
+		return backoff, true
+	}
+
+	var statusError *alert.StatusError
+	if errors.As(err, &statusError) && http.StatusTooManyRequests == statusError.Code {
+		if statusError.RetryAfter > backoff {
+			return statusError.RetryAfter, true
+		}
+		return backoff, true
+	}
+
+	var statusCode httpStatusCode
+	if errors.As(err, &statusCode) && statusCode.HTTPStatusCode() >= 500 {
+		return backoff, true
+	}
+
+	return 0, false
+}
+
+// report collect delivery error, it is returned from the next Write or Sync of the core
+func (s *sender) report(err error) {
+	s.errors.Add(fmt.Errorf("slack delivery error: %w", err))
+}
+
+// flush wait until every queued message is delivered or FlushTimeout is reached
+func (s *sender) flush() error {
+	s.lock.Lock()
+	if 0 == s.pending {
+		s.lock.Unlock()
+		return nil
+	}
+	idle := s.idle
+	s.lock.Unlock()
+
+	timer := time.NewTimer(s.options.FlushTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-idle:
+		return nil
+	case <-timer.C:
+		return errors.New("slack flush timeout")
+	}
+}
+
+// close flush queued messages and stop the worker, messages enqueued after close are dropped
+func (s *sender) close() error {
+	err := s.flush()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package slackcore
+
+import (
+	"net/http"
+
+	"github.com/payfazz/fz-sentry/alert"
+	"github.com/slack-go/slack"
+)
+
+// WebhookSink send alert to slack incoming webhook using renderer, resolved alert is ignored
+func WebhookSink(hookURL string, renderer Renderer, client *http.Client) alert.Sink {
+	return webhookSink(hookURL, renderer, client, false)
+}
+
+func webhookSink(hookURL string, renderer Renderer, client *http.Client, interactive bool) alert.Sink {
+	client = retryAfterClient(client)
+	return alert.SinkFunc(func(a alert.Alert) error {
+		if a.Resolved {
+			return nil
+		}
+		msg := renderer(a.Entry, a.Fields)
+		if interactive {
+			msg = withActions(msg, a.Fingerprint)
+		}
+		return slack.PostWebhookCustomHTTP(hookURL, client, msg)
+	})
+}
+
+// retryAfterClient add missing Retry-After header to rate limited response, slack client return parse error instead
+// of slack.RateLimitedError when the header is missing, 0 make the sender use its backoff delay
+func retryAfterClient(client *http.Client) *http.Client {
+	if nil == client {
+		client = &http.Client{Timeout: DEFAULT_HTTP_TIMEOUT}
+	}
+	result := *client
+	result.Transport = retryAfterTransport{next: client.Transport}
+	return &result
+}
+
+type retryAfterTransport struct {
+	next http.RoundTripper
+}
+
+func (t retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if nil == next {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if nil == err && http.StatusTooManyRequests == resp.StatusCode && "" == resp.Header.Get("Retry-After") {
+		resp.Header.Set("Retry-After", "0")
+	}
+	return resp, err
+}